@@ -0,0 +1,249 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+)
+
+// Primitives are the operations implemented by a logger backend.
+// Log, Logf and Logw only write the entry, they never exit or panic.
+type Primitives interface {
+	HasLevel(lv Level) bool
+	Log(lv Level, args ...interface{})
+	Logf(lv Level, format string, args ...interface{})
+	Logw(lv Level, msg string, keyVals ...interface{})
+}
+
+// LoggerBase derives level specific methods of Logger from Primitives.
+// A backend embeds LoggerBase together with LevelLoggerBase, e.g.
+//
+//	type myLogger struct {
+//		slog.LevelLoggerBase
+//		slog.LoggerBase
+//	}
+//
+//	ml := &myLogger{}
+//	ml.LoggerBase = slog.NewLoggerBase(ml)
+//
+// Fatal methods call Exit(1) of the primitives if implemented, otherwise os.Exit(1).
+type LoggerBase struct {
+	p Primitives
+}
+
+// NewLoggerBase return base which forwards to given primitives
+func NewLoggerBase(p Primitives) LoggerBase {
+	return LoggerBase{p: p}
+}
+
+func (b LoggerBase) exit() {
+	if e, ok := b.p.(interface{ Exit(code int) }); ok {
+		e.Exit(1)
+		return
+	}
+	os.Exit(1)
+}
+
+func (b LoggerBase) log(lv Level, args []interface{}) {
+	if b.p.HasLevel(lv) {
+		b.p.Log(lv, args...)
+	}
+}
+
+func (b LoggerBase) logln(lv Level, args []interface{}) {
+	if b.p.HasLevel(lv) {
+		b.p.Log(lv, sprintln(args))
+	}
+}
+
+func (b LoggerBase) logf(lv Level, format string, args []interface{}) {
+	if b.p.HasLevel(lv) {
+		b.p.Logf(lv, format, args...)
+	}
+}
+
+func (b LoggerBase) logw(lv Level, msg string, keyVals []interface{}) {
+	if b.p.HasLevel(lv) {
+		b.p.Logw(lv, msg, keyVals...)
+	}
+}
+
+// sprintln formats using fmt.Sprintln without trailing new line
+func sprintln(args []interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
+
+func (b LoggerBase) Trace(args ...interface{}) {
+	b.log(TraceLevel, args)
+}
+func (b LoggerBase) Debug(args ...interface{}) {
+	b.log(DebugLevel, args)
+}
+func (b LoggerBase) Print(args ...interface{}) {
+	b.log(InfoLevel, args)
+}
+func (b LoggerBase) Info(args ...interface{}) {
+	b.log(InfoLevel, args)
+}
+func (b LoggerBase) Warn(args ...interface{}) {
+	b.log(WarnLevel, args)
+}
+func (b LoggerBase) Error(args ...interface{}) {
+	b.log(ErrorLevel, args)
+}
+func (b LoggerBase) Fatal(args ...interface{}) {
+	b.log(FatalLevel, args)
+	b.exit()
+}
+func (b LoggerBase) Panic(args ...interface{}) {
+	b.log(PanicLevel, args)
+	panic(fmt.Sprint(args...))
+}
+
+func (b LoggerBase) Traceln(args ...interface{}) {
+	b.logln(TraceLevel, args)
+}
+func (b LoggerBase) Debugln(args ...interface{}) {
+	b.logln(DebugLevel, args)
+}
+func (b LoggerBase) Println(args ...interface{}) {
+	b.logln(InfoLevel, args)
+}
+func (b LoggerBase) Infoln(args ...interface{}) {
+	b.logln(InfoLevel, args)
+}
+func (b LoggerBase) Warnln(args ...interface{}) {
+	b.logln(WarnLevel, args)
+}
+func (b LoggerBase) Errorln(args ...interface{}) {
+	b.logln(ErrorLevel, args)
+}
+func (b LoggerBase) Fatalln(args ...interface{}) {
+	b.logln(FatalLevel, args)
+	b.exit()
+}
+func (b LoggerBase) Panicln(args ...interface{}) {
+	b.logln(PanicLevel, args)
+	panic(fmt.Sprintln(args...))
+}
+
+func (b LoggerBase) Tracef(format string, args ...interface{}) {
+	b.logf(TraceLevel, format, args)
+}
+func (b LoggerBase) Debugf(format string, args ...interface{}) {
+	b.logf(DebugLevel, format, args)
+}
+func (b LoggerBase) Printf(format string, args ...interface{}) {
+	b.logf(InfoLevel, format, args)
+}
+func (b LoggerBase) Infof(format string, args ...interface{}) {
+	b.logf(InfoLevel, format, args)
+}
+func (b LoggerBase) Warnf(format string, args ...interface{}) {
+	b.logf(WarnLevel, format, args)
+}
+func (b LoggerBase) Errorf(format string, args ...interface{}) {
+	b.logf(ErrorLevel, format, args)
+}
+func (b LoggerBase) Fatalf(format string, args ...interface{}) {
+	b.logf(FatalLevel, format, args)
+	b.exit()
+}
+func (b LoggerBase) Panicf(format string, args ...interface{}) {
+	b.logf(PanicLevel, format, args)
+	panic(fmt.Sprintf(format, args...))
+}
+
+func (b LoggerBase) Tracew(msg string, keyVals ...interface{}) {
+	b.logw(TraceLevel, msg, keyVals)
+}
+func (b LoggerBase) Debugw(msg string, keyVals ...interface{}) {
+	b.logw(DebugLevel, msg, keyVals)
+}
+func (b LoggerBase) Printw(msg string, keyVals ...interface{}) {
+	b.logw(InfoLevel, msg, keyVals)
+}
+func (b LoggerBase) Infow(msg string, keyVals ...interface{}) {
+	b.logw(InfoLevel, msg, keyVals)
+}
+func (b LoggerBase) Warnw(msg string, keyVals ...interface{}) {
+	b.logw(WarnLevel, msg, keyVals)
+}
+func (b LoggerBase) Errorw(msg string, keyVals ...interface{}) {
+	b.logw(ErrorLevel, msg, keyVals)
+}
+func (b LoggerBase) Fatalw(msg string, keyVals ...interface{}) {
+	b.logw(FatalLevel, msg, keyVals)
+	b.exit()
+}
+func (b LoggerBase) Panicw(msg string, keyVals ...interface{}) {
+	b.logw(PanicLevel, msg, keyVals)
+	panic(SimpleFormatter(msg, keyVals, "="))
+}
+
+// Leveled return l if it implements LevelLogger, otherwise l is wrapped with adapter which
+// writes entries using the level specific method of the nearest built-in level (see Level.Builtin).
+func Leveled(l Logger) LevelLogger {
+	if ll, ok := l.(LevelLogger); ok {
+		return ll
+	}
+	return levelAdapter{l}
+}
+
+// levelAdapter implements LevelLogger using level specific methods of Logger.
+// PanicLevel and FatalLevel are written at ErrorLevel, since Logger can not write them
+// without panic or exit, which Log, Logf and Logw never do. Panics raised by the
+// wrapped logger are not recovered.
+type levelAdapter struct {
+	Logger
+}
+
+// Unwrap return adapted logger
+func (la levelAdapter) Unwrap() Logger {
+	return la.Logger
+}
+
+func (la levelAdapter) Log(lv Level, args ...interface{}) {
+	switch lv.Builtin() {
+	case PanicLevel, FatalLevel, ErrorLevel:
+		la.Error(args...)
+	case WarnLevel:
+		la.Warn(args...)
+	case InfoLevel:
+		la.Info(args...)
+	case DebugLevel:
+		la.Debug(args...)
+	default:
+		la.Trace(args...)
+	}
+}
+
+func (la levelAdapter) Logf(lv Level, format string, args ...interface{}) {
+	switch lv.Builtin() {
+	case PanicLevel, FatalLevel, ErrorLevel:
+		la.Errorf(format, args...)
+	case WarnLevel:
+		la.Warnf(format, args...)
+	case InfoLevel:
+		la.Infof(format, args...)
+	case DebugLevel:
+		la.Debugf(format, args...)
+	default:
+		la.Tracef(format, args...)
+	}
+}
+
+func (la levelAdapter) Logw(lv Level, msg string, keyVals ...interface{}) {
+	switch lv.Builtin() {
+	case PanicLevel, FatalLevel, ErrorLevel:
+		la.Errorw(msg, keyVals...)
+	case WarnLevel:
+		la.Warnw(msg, keyVals...)
+	case InfoLevel:
+		la.Infow(msg, keyVals...)
+	case DebugLevel:
+		la.Debugw(msg, keyVals...)
+	default:
+		la.Tracew(msg, keyVals...)
+	}
+}
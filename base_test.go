@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"fmt"
+	"testing"
+)
+
+// methodLogger is external Logger without Log, Logf and Logw,
+// it records name of the called method
+type methodLogger struct {
+	Logger
+	calls []string
+}
+
+func (ml *methodLogger) record(name string) {
+	ml.calls = append(ml.calls, name)
+}
+
+func (ml *methodLogger) last() string {
+	if len(ml.calls) == 0 {
+		return ""
+	}
+	return ml.calls[len(ml.calls)-1]
+}
+
+func (ml *methodLogger) HasLevel(lv Level) bool { return true }
+
+func (ml *methodLogger) Trace(args ...interface{}) { ml.record("Trace") }
+func (ml *methodLogger) Info(args ...interface{})  { ml.record("Info") }
+func (ml *methodLogger) Error(args ...interface{}) { ml.record("Error") }
+
+func (ml *methodLogger) Debugf(format string, args ...interface{}) { ml.record("Debugf") }
+func (ml *methodLogger) Errorf(format string, args ...interface{}) { ml.record("Errorf") }
+
+func (ml *methodLogger) Infow(msg string, keyVals ...interface{})  { ml.record("Infow") }
+func (ml *methodLogger) Warnw(msg string, keyVals ...interface{})  { ml.record("Warnw") }
+func (ml *methodLogger) Errorw(msg string, keyVals ...interface{}) { ml.record("Errorw") }
+func (ml *methodLogger) Panicw(msg string, keyVals ...interface{}) {
+	ml.record("Panicw")
+	panic(msg)
+}
+
+func TestLeveledAdapter(t *testing.T) {
+	notice := MustRegisterLevel(LevelSpec{Name: "adapter-notice", Fixed: "NOTIC", Severity: InfoSeverity + 50})
+
+	tests := []struct {
+		name string
+		call func(l LevelLogger)
+		want string
+	}{
+		{"log info", func(l LevelLogger) { l.Log(InfoLevel, "msg") }, "Info"},
+		{"log fatal at error", func(l LevelLogger) { l.Log(FatalLevel, "msg") }, "Error"},
+		{"log custom level", func(l LevelLogger) { l.Log(notice, "msg") }, "Info"},
+		{"logf debug", func(l LevelLogger) { l.Logf(DebugLevel, "%d", 1) }, "Debugf"},
+		{"logf fatal at error", func(l LevelLogger) { l.Logf(FatalLevel, "%d", 1) }, "Errorf"},
+		{"logw warn", func(l LevelLogger) { l.Logw(WarnLevel, "msg", "k", 1) }, "Warnw"},
+		{"logw custom level", func(l LevelLogger) { l.Logw(notice, "msg") }, "Infow"},
+		{"logw panic at error", func(l LevelLogger) { l.Logw(PanicLevel, "msg") }, "Errorw"},
+		{"log panic at error", func(l LevelLogger) { l.Log(PanicLevel, "msg") }, "Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml := &methodLogger{}
+			tt.call(Leveled(ml))
+			if got := ml.last(); got != tt.want {
+				t.Errorf("called %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// brokenLogger panics while writing, e.g. failing encoder
+type brokenLogger struct {
+	Logger
+}
+
+func (brokenLogger) Errorw(msg string, keyVals ...interface{}) { panic("encoder bug") }
+
+func TestLeveledAdapterDoesNotRecover(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "encoder bug" {
+			t.Errorf("recovered %v, want panic of wrapped logger", r)
+		}
+	}()
+	Leveled(brokenLogger{}).Logw(PanicLevel, "msg")
+}
+
+func TestLeveledKeepsLevelLogger(t *testing.T) {
+	if _, ok := Leveled(Discard).(levelAdapter); ok {
+		t.Error("LevelLogger is wrapped with adapter")
+	}
+}
+
+func TestExternalLogger(t *testing.T) {
+	restoreDefault(t)
+	tests := []struct {
+		name string
+		call func(l Logger)
+		want string
+	}{
+		{"package Logw", func(l Logger) {
+			SetDefault(l)
+			Logw(WarnLevel, "msg")
+		}, "Warnw"},
+		{"package Log", func(l Logger) {
+			SetDefault(l)
+			Log(TraceLevel, "msg")
+		}, "Trace"},
+		{"hooked logger", func(l Logger) {
+			NewHookedLogger(l, HookFunc(func(e *Entry) error { return nil })).Errorw("msg", "k", 1)
+		}, "Errorw"},
+		{"group logger", func(l Logger) {
+			WithGroup(l, "req").Infow("msg", "k", 1)
+		}, "Infow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml := &methodLogger{}
+			tt.call(ml)
+			if got := ml.last(); got != tt.want {
+				t.Errorf("called %q, want %q (calls %s)", got, tt.want, fmt.Sprint(ml.calls))
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorDepth is number of colors supported by terminal
+type ColorDepth int
+
+// Supported color depth, 256 and true colors are converted to
+// the nearest color when terminal supports less colors
+const (
+	Color16 ColorDepth = iota
+	Color256
+	ColorTrue
+)
+
+// ColorEnabled reports whether colors should be written to w.
+// FORCE_COLOR (other than 0) or CLICOLOR_FORCE enable colors, otherwise
+// NO_COLOR, CLICOLOR=0 or TERM=dumb disable them. If none is set,
+// colors are enabled when w is a terminal.
+func ColorEnabled(w io.Writer) bool {
+	if v := os.Getenv("FORCE_COLOR"); v != "" {
+		return v != "0" && v != "false"
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// IsTerminal reports whether w is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// DetectColorDepth return color depth from FORCE_COLOR (1, 2 or 3), COLORTERM and TERM
+func DetectColorDepth() ColorDepth {
+	switch os.Getenv("FORCE_COLOR") {
+	case "2":
+		return Color256
+	case "3":
+		return ColorTrue
+	}
+	if ct := os.Getenv("COLORTERM"); ct == "truecolor" || ct == "24bit" {
+		return ColorTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color16
+}
+
+type colorKind uint8
+
+const (
+	noColor colorKind = iota
+	basicColor
+	paletteColor
+	rgbColor
+)
+
+type colorValue struct {
+	kind    colorKind
+	n       uint8
+	r, g, b uint8
+}
+
+// Color is text style of terminal output, see ParseColor
+type Color struct {
+	attrs  []int
+	fg, bg colorValue
+}
+
+var colorNames = map[string]uint8{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"gray": 8, "grey": 8,
+}
+
+var colorAttrs = map[string]int{
+	"bold": 1, "dim": 2, "faint": 2, "italic": 3, "underline": 4, "blink": 5, "reverse": 7, "strike": 9,
+}
+
+// ParseColor parses style specification, words are separated by space or '+', e.g.
+// `bold+red`, `hi-green`, `dim`, `208` (256 colors), `#ff8800` (true color) or
+// `white on #005f87` (`on` or `bg:` prefix selects background).
+// Color names are black, red, green, yellow, blue, magenta, cyan, white and gray,
+// prefixed with `hi-` or `bright-` for bright variant. Empty spec means no style.
+func ParseColor(spec string) (Color, error) {
+	var c Color
+	bg := false
+	words := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ' ' || r == '+'
+	})
+	for _, w := range words {
+		if w == "on" {
+			bg = true
+			continue
+		}
+		if strings.HasPrefix(w, "bg:") {
+			bg, w = true, w[3:]
+		}
+		if attr, ok := colorAttrs[w]; ok && !bg {
+			c.attrs = append(c.attrs, attr)
+			continue
+		}
+		cv, ok := parseColorValue(w)
+		if !ok {
+			return Color{}, fmt.Errorf("slog: invalid color %q in %q", w, spec)
+		}
+		if bg {
+			c.bg = cv
+		} else {
+			c.fg = cv
+		}
+		bg = false
+	}
+	return c, nil
+}
+
+// MustParseColor is like ParseColor but panics if spec is invalid
+func MustParseColor(spec string) Color {
+	c, err := ParseColor(spec)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseColorValue(w string) (colorValue, bool) {
+	if n, ok := colorNames[w]; ok {
+		return colorValue{kind: basicColor, n: n}, true
+	}
+	for _, prefix := range []string{"hi-", "hi", "bright-", "bright"} {
+		if n, ok := colorNames[strings.TrimPrefix(w, prefix)]; ok && strings.HasPrefix(w, prefix) && n < 8 {
+			return colorValue{kind: basicColor, n: n + 8}, true
+		}
+	}
+	if strings.HasPrefix(w, "#") {
+		hex := w[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return colorValue{}, false
+		}
+		return colorValue{kind: rgbColor, r: uint8(v >> 16), g: uint8(v >> 8), b: uint8(v)}, true
+	}
+	if n, err := strconv.ParseUint(w, 10, 8); err == nil {
+		return colorValue{kind: paletteColor, n: uint8(n)}, true
+	}
+	return colorValue{}, false
+}
+
+// IsZero reports whether no style is set
+func (c Color) IsZero() bool {
+	return len(c.attrs) == 0 && c.fg.kind == noColor && c.bg.kind == noColor
+}
+
+// Wrap s with escape sequences of the style for terminal with given depth
+func (c Color) Wrap(s string, depth ColorDepth) string {
+	if c.IsZero() {
+		return s
+	}
+	codes := make([]string, 0, len(c.attrs)+2)
+	for _, attr := range c.attrs {
+		codes = append(codes, strconv.Itoa(attr))
+	}
+	if c.fg.kind != noColor {
+		codes = append(codes, c.fg.code(depth, 30))
+	}
+	if c.bg.kind != noColor {
+		codes = append(codes, c.bg.code(depth, 40))
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + s + "\x1b[0m"
+}
+
+// code return SGR parameter, base is 30 for foreground and 40 for background
+func (cv colorValue) code(depth ColorDepth, base int) string {
+	switch cv.kind {
+	case rgbColor:
+		if depth >= ColorTrue {
+			return fmt.Sprintf("%d;2;%d;%d;%d", base+8, cv.r, cv.g, cv.b)
+		}
+		if depth == Color256 {
+			return fmt.Sprintf("%d;5;%d", base+8, rgbToPalette(cv.r, cv.g, cv.b))
+		}
+		return basicCode(rgbToBasic(cv.r, cv.g, cv.b), base)
+	case paletteColor:
+		if depth >= Color256 {
+			return fmt.Sprintf("%d;5;%d", base+8, cv.n)
+		}
+		if cv.n < 16 {
+			return basicCode(cv.n, base)
+		}
+		r, g, b := paletteToRGB(cv.n)
+		return basicCode(rgbToBasic(r, g, b), base)
+	}
+	return basicCode(cv.n, base)
+}
+
+func basicCode(n uint8, base int) string {
+	if n >= 8 {
+		return strconv.Itoa(base + 60 + int(n-8))
+	}
+	return strconv.Itoa(base + int(n))
+}
+
+// levels of 6x6x6 color cube in 256 colors palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+func rgbToPalette(r, g, b uint8) uint8 {
+	if r == g && g == b {
+		switch {
+		case r < 8:
+			return 16
+		case r > 248:
+			return 231
+		}
+		return uint8(232 + (int(r)-8)*24/241)
+	}
+	// nearest level of the color cube
+	q := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if d, bd := l-int(v), cubeLevels[best]-int(v); d*d < bd*bd {
+				best = i
+			}
+		}
+		return best
+	}
+	return uint8(16 + 36*q(r) + 6*q(g) + q(b))
+}
+
+func paletteToRGB(n uint8) (r, g, b uint8) {
+	if n >= 232 {
+		v := uint8(8 + 10*(int(n)-232))
+		return v, v, v
+	}
+	n -= 16
+	return uint8(cubeLevels[n/36]), uint8(cubeLevels[n/6%6]), uint8(cubeLevels[n%6])
+}
+
+// xterm default 16 colors
+var basicRGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+func rgbToBasic(r, g, b uint8) uint8 {
+	best, bestDist := 0, -1
+	for i, c := range basicRGB {
+		dr, dg, db := int(r)-c[0], int(g)-c[1], int(b)-c[2]
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// Theme of colored text output. Key without color is written using level color.
+type Theme struct {
+	Levels    map[Level]Color
+	Key       Color
+	Value     Color
+	Timestamp Color
+}
+
+// DefaultTheme return theme used by text encoder
+func DefaultTheme() *Theme {
+	return &Theme{
+		Levels: map[Level]Color{
+			PanicLevel: MustParseColor("hi-red"),
+			FatalLevel: MustParseColor("hi-magenta"),
+			ErrorLevel: MustParseColor("red"),
+			WarnLevel:  MustParseColor("yellow"),
+			InfoLevel:  MustParseColor("green"),
+			DebugLevel: MustParseColor("blue"),
+			TraceLevel: MustParseColor("cyan"),
+		},
+	}
+}
+
+// Option names of theme
+const (
+	fieldColor          = "color"
+	fieldTheme          = "theme"
+	fieldThemeLevels    = "levels"
+	fieldThemeKey       = "key"
+	fieldThemeValue     = "value"
+	fieldThemeTimestamp = "timestamp"
+)
+
+// ThemeFromOptions return default theme modified by options, e.g.
+//
+//	Options{"levels": Options{"info": "#5fd700", "error": "bold red"}, "key": "cyan", "timestamp": "dim"}
+func ThemeFromOptions(op Options) (*Theme, error) {
+	t := DefaultTheme()
+	for name, spec := range op.GetOptions(fieldThemeLevels) {
+		lv, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		s, _ := toString(spec)
+		if t.Levels[lv], err = ParseColor(s); err != nil {
+			return nil, err
+		}
+	}
+	for _, it := range []struct {
+		key string
+		c   *Color
+	}{
+		{fieldThemeKey, &t.Key},
+		{fieldThemeValue, &t.Value},
+		{fieldThemeTimestamp, &t.Timestamp},
+	} {
+		var err error
+		if *it.c, err = ParseColor(op.GetString(it.key, "")); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// level color of the theme, level without theme color uses LevelSpec.Color
+func (t *Theme) level(lv Level, depth ColorDepth, s string) string {
+	if c, ok := t.Levels[lv]; ok {
+		return c.Wrap(s, depth)
+	}
+	if spec, ok := lv.Spec(); ok {
+		return spec.Color.Wrap(s, depth)
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package slog
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec  string
+		depth ColorDepth
+		want  string
+	}{
+		{"", ColorTrue, "x"},
+		{"red", Color16, "\x1b[31mx\x1b[0m"},
+		{"Hi-Green", Color16, "\x1b[92mx\x1b[0m"},
+		{"bold+red", Color16, "\x1b[1;31mx\x1b[0m"},
+		{"dim", Color16, "\x1b[2mx\x1b[0m"},
+		{"#ff8800", ColorTrue, "\x1b[38;2;255;136;0mx\x1b[0m"},
+		{"#f80", Color256, "\x1b[38;5;208mx\x1b[0m"},
+		{"#ff8800", Color16, "\x1b[33mx\x1b[0m"},
+		{"208", Color256, "\x1b[38;5;208mx\x1b[0m"},
+		{"208", Color16, "\x1b[33mx\x1b[0m"},
+		{"9", Color16, "\x1b[91mx\x1b[0m"},
+		{"white on #005f87", Color256, "\x1b[37;48;5;24mx\x1b[0m"},
+		{"bg:blue", Color16, "\x1b[44mx\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			c, err := ParseColor(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Wrap("x", tt.depth); got != tt.want {
+				t.Errorf("Wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"purple", "#12345", "#gggggg", "256", "on bold"} {
+		if _, err := ParseColor(spec); err == nil {
+			t.Errorf("ParseColor(%q) accepted invalid color", spec)
+		}
+	}
+}
+
+// setenv sets environment variables and restores them on cleanup
+func setenv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		old, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	reset := map[string]string{"FORCE_COLOR": "", "CLICOLOR_FORCE": "", "NO_COLOR": "", "CLICOLOR": "", "TERM": "xterm"}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"not a terminal", nil, false},
+		{"force color", map[string]string{"FORCE_COLOR": "1"}, true},
+		{"force color off", map[string]string{"FORCE_COLOR": "0", "CLICOLOR_FORCE": "1"}, false},
+		{"clicolor force", map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, true},
+		{"no color", map[string]string{"NO_COLOR": "1"}, false},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setenv(t, reset)
+			setenv(t, tt.env)
+			if got := ColorEnabled(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("ColorEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want ColorDepth
+	}{
+		{map[string]string{}, Color16},
+		{map[string]string{"TERM": "xterm-256color"}, Color256},
+		{map[string]string{"COLORTERM": "truecolor"}, ColorTrue},
+		{map[string]string{"FORCE_COLOR": "2"}, Color256},
+		{map[string]string{"FORCE_COLOR": "3", "TERM": "xterm-256color"}, ColorTrue},
+	}
+	for _, tt := range tests {
+		setenv(t, map[string]string{"FORCE_COLOR": "", "COLORTERM": "", "TERM": "xterm"})
+		setenv(t, tt.env)
+		if got := DetectColorDepth(); got != tt.want {
+			t.Errorf("DetectColorDepth() with %v = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestThemeLevel(t *testing.T) {
+	styled := MustRegisterLevel(LevelSpec{Name: "color-notice", Fixed: "NOTIC", Color: MustParseColor("hi-cyan"), Severity: InfoSeverity + 50})
+	plain := MustRegisterLevel(LevelSpec{Name: "color-plain", Fixed: "PLAIN", Severity: InfoSeverity + 60})
+	theme, err := ThemeFromOptions(Options{"levels": Options{"INFO": "bold", "color-plain": "#ff0000"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		theme *Theme
+		lv    Level
+		want  string
+	}{
+		{"default theme", DefaultTheme(), InfoLevel, "\x1b[32mx\x1b[0m"},
+		{"level spec color", DefaultTheme(), styled, "\x1b[96mx\x1b[0m"},
+		{"level without color", DefaultTheme(), plain, "x"},
+		{"theme overrides builtin", theme, InfoLevel, "\x1b[1mx\x1b[0m"},
+		{"theme overrides user level", theme, plain, "\x1b[38;2;255;0;0mx\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.theme.level(tt.lv, ColorTrue, "x"); got != tt.want {
+				t.Errorf("level() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, op := range []Options{
+		{"levels": Options{"nosuch": "red"}},
+		{"levels": Options{"info": "purple"}},
+		{"key": "purple"},
+	} {
+		if _, err := ThemeFromOptions(op); err == nil {
+			t.Errorf("ThemeFromOptions(%v) accepted invalid theme", op)
+		}
+	}
+}
+
+func TestStdLoggerColorOption(t *testing.T) {
+	tests := []struct {
+		color     string
+		wantColor bool
+	}{
+		{"always", true},
+		{"Always", true},
+		{"NEVER", false},
+		{"auto", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			setenv(t, map[string]string{"FORCE_COLOR": "", "CLICOLOR_FORCE": ""})
+			var buf bytes.Buffer
+			lg, err := NewStdLogger(&buf, InfoLevel, Options{"color": tt.color, "timestampFormat": "none"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg.Info("hello")
+			if got := bytes.Contains(buf.Bytes(), []byte("\x1b[")); got != tt.wantColor {
+				t.Errorf("colored = %v, want %v: %q", got, tt.wantColor, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package slog
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// DefaultLogger forwards every call to the logger returned by Default.
+// For compatibility, logger assigned to this variable is used by package level
+// functions and takes precedence over SetDefault until the variable is reset
+// to nil. Assignment is not safe for concurrent use.
+//
+// Deprecated: use Default and SetDefault instead.
+var DefaultLogger Logger = defaultLogger{}
+
+// holder of package logger, atomic.Value requires consistent concrete type
+type loggerHolder struct {
+	Logger
+}
+
+var defaultL atomic.Value
+
+func init() {
+	defaultL.Store(loggerHolder{Discard})
+
+	// Switch to STD Logger
+	if lgr, err := NewStdLogger(os.Stdout, TraceLevel, nil); err == nil {
+		SetDefault(lgr)
+	}
+}
+
+// Default return logger used by package level functions
+func Default() Logger {
+	switch l := DefaultLogger; l.(type) {
+	case nil, defaultLogger, *defaultLogger:
+	default:
+		// reassigned by caller
+		return l
+	}
+	return defaultL.Load().(loggerHolder).Logger
+}
+
+// SetDefault replace logger used by package level functions.
+// Nil logger is replaced with Discard logger.
+func SetDefault(l Logger) {
+	switch l.(type) {
+	case nil:
+		l = Discard
+	case defaultLogger, *defaultLogger:
+		// forwarding to itself
+		return
+	}
+	defaultL.Store(loggerHolder{l})
+}
+
+// defaultLogger forwards call to current package logger
+type defaultLogger struct{}
+
+func (defaultLogger) HasLevel(lv Level) bool {
+	return HasLevel(lv)
+}
+func (defaultLogger) SetLevel(lv Level) {
+	SetLevel(lv)
+}
+
+func (defaultLogger) Log(lv Level, args ...interface{}) {
+	Log(lv, args...)
+}
+func (defaultLogger) Logf(lv Level, format string, args ...interface{}) {
+	Logf(lv, format, args...)
+}
+func (defaultLogger) Logw(lv Level, msg string, keyVals ...interface{}) {
+	Logw(lv, msg, keyVals...)
+}
+
+func (defaultLogger) Trace(args ...interface{}) {
+	Default().Trace(args...)
+}
+func (defaultLogger) Debug(args ...interface{}) {
+	Default().Debug(args...)
+}
+func (defaultLogger) Print(args ...interface{}) {
+	Default().Print(args...)
+}
+func (defaultLogger) Info(args ...interface{}) {
+	Default().Info(args...)
+}
+func (defaultLogger) Warn(args ...interface{}) {
+	Default().Warn(args...)
+}
+func (defaultLogger) Error(args ...interface{}) {
+	Default().Error(args...)
+}
+func (defaultLogger) Fatal(args ...interface{}) {
+	Default().Fatal(args...)
+}
+func (defaultLogger) Panic(args ...interface{}) {
+	Default().Panic(args...)
+}
+
+func (defaultLogger) Traceln(args ...interface{}) {
+	Default().Traceln(args...)
+}
+func (defaultLogger) Debugln(args ...interface{}) {
+	Default().Debugln(args...)
+}
+func (defaultLogger) Println(args ...interface{}) {
+	Default().Println(args...)
+}
+func (defaultLogger) Infoln(args ...interface{}) {
+	Default().Infoln(args...)
+}
+func (defaultLogger) Warnln(args ...interface{}) {
+	Default().Warnln(args...)
+}
+func (defaultLogger) Errorln(args ...interface{}) {
+	Default().Errorln(args...)
+}
+func (defaultLogger) Fatalln(args ...interface{}) {
+	Default().Fatalln(args...)
+}
+func (defaultLogger) Panicln(args ...interface{}) {
+	Default().Panicln(args...)
+}
+
+func (defaultLogger) Tracef(format string, args ...interface{}) {
+	Default().Tracef(format, args...)
+}
+func (defaultLogger) Debugf(format string, args ...interface{}) {
+	Default().Debugf(format, args...)
+}
+func (defaultLogger) Printf(format string, args ...interface{}) {
+	Default().Printf(format, args...)
+}
+func (defaultLogger) Infof(format string, args ...interface{}) {
+	Default().Infof(format, args...)
+}
+func (defaultLogger) Warnf(format string, args ...interface{}) {
+	Default().Warnf(format, args...)
+}
+func (defaultLogger) Errorf(format string, args ...interface{}) {
+	Default().Errorf(format, args...)
+}
+func (defaultLogger) Fatalf(format string, args ...interface{}) {
+	Default().Fatalf(format, args...)
+}
+func (defaultLogger) Panicf(format string, args ...interface{}) {
+	Default().Panicf(format, args...)
+}
+
+func (defaultLogger) Tracew(msg string, keyVals ...interface{}) {
+	Default().Tracew(msg, keyVals...)
+}
+func (defaultLogger) Debugw(msg string, keyVals ...interface{}) {
+	Default().Debugw(msg, keyVals...)
+}
+func (defaultLogger) Printw(msg string, keyVals ...interface{}) {
+	Default().Printw(msg, keyVals...)
+}
+func (defaultLogger) Infow(msg string, keyVals ...interface{}) {
+	Default().Infow(msg, keyVals...)
+}
+func (defaultLogger) Warnw(msg string, keyVals ...interface{}) {
+	Default().Warnw(msg, keyVals...)
+}
+func (defaultLogger) Errorw(msg string, keyVals ...interface{}) {
+	Default().Errorw(msg, keyVals...)
+}
+func (defaultLogger) Fatalw(msg string, keyVals ...interface{}) {
+	Default().Fatalw(msg, keyVals...)
+}
+func (defaultLogger) Panicw(msg string, keyVals ...interface{}) {
+	Default().Panicw(msg, keyVals...)
+}
@@ -0,0 +1,81 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func restoreDefault(t *testing.T) {
+	old := Default()
+	t.Cleanup(func() {
+		DefaultLogger = defaultLogger{}
+		SetDefault(old)
+	})
+}
+
+func TestSetDefaultConcurrent(t *testing.T) {
+	restoreDefault(t)
+	var out syncBuffer
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Infow("concurrent", "j", j)
+				DefaultLogger.Info("forwarded")
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if (i+j)%2 == 0 {
+					l, _ := NewStdLogger(&out, InfoLevel, Options{"formatter": "logfmt"})
+					SetDefault(l)
+				} else if err := UseWithOptions(StdLoggerName, &out, InfoLevel, Options{"formatter": "json"}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDefaultLoggerAssignment(t *testing.T) {
+	restoreDefault(t)
+	var set, assigned bytes.Buffer
+	l, _ := NewStdLogger(&set, InfoLevel, Options{"formatter": "logfmt"})
+	SetDefault(l)
+
+	DefaultLogger, _ = NewStdLogger(&assigned, InfoLevel, Options{"formatter": "logfmt"})
+	Info("to assigned")
+	DefaultLogger = nil
+	Info("to default")
+
+	if !strings.Contains(assigned.String(), "to assigned") || strings.Contains(assigned.String(), "to default") {
+		t.Errorf("assigned logger got %q", assigned.String())
+	}
+	if !strings.Contains(set.String(), "to default") || strings.Contains(set.String(), "to assigned") {
+		t.Errorf("default logger got %q", set.String())
+	}
+}
@@ -0,0 +1,156 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ECSVersion written as ecs.version
+const ECSVersion = "1.6.0"
+
+// maximum suffix tried for field which clashes under `fields`
+const maxECSClashSuffix = 100
+
+// ECSEncoder writes entry as Elastic Common Schema JSON object.
+// Dotted field keys are nested into objects (e.g. `http.request.method`),
+// error in `error` or `err` field is written as error.message, error.type and
+// error.stack_trace (when formatting with %+v adds detail, e.g. pkg/errors).
+// Field which clashes with ECS field of the entry is written under `fields`,
+// with numeric suffix when that key is taken too (`fields.key_2`). Field which
+// still can not be placed (e.g. its parent is a scalar) is dropped and reported
+// through ReportError.
+type ECSEncoder struct{}
+
+// ordered JSON object, value is either *ecsObject or field value
+type ecsObject struct {
+	keys []string
+	vals map[string]interface{}
+}
+
+func newECSObject() *ecsObject {
+	return &ecsObject{vals: make(map[string]interface{})}
+}
+
+// set value at dotted path, return false if it clashes with existing value.
+// Nothing is added when the path clashes.
+func (o *ecsObject) set(path string, val interface{}) bool {
+	parts := strings.Split(path, ".")
+	for _, p := range parts {
+		if p == "" {
+			return o.put(path, val)
+		}
+	}
+
+	// check the whole path before creating intermediate objects
+	cur := o
+	for i, p := range parts {
+		v, ok := cur.vals[p]
+		if !ok {
+			break
+		}
+		child, isObj := v.(*ecsObject)
+		if !isObj || i == len(parts)-1 {
+			return false
+		}
+		cur = child
+	}
+
+	cur = o
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := cur.vals[p].(*ecsObject)
+		if !ok {
+			child = newECSObject()
+			cur.put(p, child)
+		}
+		cur = child
+	}
+	return cur.put(parts[len(parts)-1], val)
+}
+
+// setClash writes field which clashes with ECS or earlier field under `fields`,
+// adding numeric suffix (`fields.key_2`, ...) when that key is taken too.
+func (o *ecsObject) setClash(key string, val interface{}) bool {
+	path := fieldClashPrefix + key
+	for i := 2; i <= maxECSClashSuffix; i++ {
+		if o.set(path, val) {
+			return true
+		}
+		path = fmt.Sprintf("%s%s_%d", fieldClashPrefix, key, i)
+	}
+	return false
+}
+
+func (o *ecsObject) put(key string, val interface{}) bool {
+	if _, ok := o.vals[key]; ok {
+		return false
+	}
+	o.keys = append(o.keys, key)
+	o.vals[key] = val
+	return true
+}
+
+func (o *ecsObject) write(buf *bytes.Buffer) {
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		writeJSONKey(buf, k, i == 0)
+		if child, ok := o.vals[k].(*ecsObject); ok {
+			child.write(buf)
+		} else {
+			writeJSONValue(buf, o.vals[k])
+		}
+	}
+	buf.WriteByte('}')
+}
+
+// ContentType of encoded entry
+func (enc *ECSEncoder) ContentType() string {
+	return ContentTypeJSON
+}
+
+// Encode entry as ECS JSON
+func (enc *ECSEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	o := newECSObject()
+	o.set("@timestamp", e.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	o.set("log.level", e.Level.String())
+	if e.Logger != "" {
+		o.set("log.logger", e.Logger)
+	}
+	if e.Caller != nil {
+		o.set("log.origin.file.name", e.Caller.File)
+		o.set("log.origin.file.line", e.Caller.Line)
+		if e.Caller.Function != "" {
+			o.set("log.origin.function", e.Caller.Function)
+		}
+	}
+	o.set("message", e.Message)
+	o.set("ecs.version", ECSVersion)
+	if sc, ok := e.Span(); ok {
+		o.set("trace.id", sc.TraceID)
+		o.set("span.id", sc.SpanID)
+	}
+
+	for _, f := range FlattenFields(e.Fields) {
+		if err, ok := f.Value.(error); ok && (f.Key == "error" || f.Key == "err") {
+			setECSError(o, err)
+			continue
+		}
+		if !o.set(f.Key, f.Value) && !o.setClash(f.Key, f.Value) {
+			ReportError(fmt.Errorf("ecs: field %q clashes with existing field and is dropped", f.Key))
+		}
+	}
+
+	o.write(buf)
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+func setECSError(o *ecsObject, err error) {
+	msg := err.Error()
+	o.set("error.message", msg)
+	o.set("error.type", errorType(err))
+	if st := fmt.Sprintf("%+v", err); st != msg {
+		o.set("error.stack_trace", st)
+	}
+}
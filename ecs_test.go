@@ -0,0 +1,103 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// error which adds detail when formatted with %+v
+type stackError struct{ msg string }
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nmain.run\n\tmain.go:12", e.msg)
+		return
+	}
+	fmt.Fprint(s, e.msg)
+}
+
+func TestECSEncoder(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("WIB", 7*3600))
+	head := `{"@timestamp":"2024-05-06T00:08:09.123Z","log":{"level":"info"},"message":"m","ecs":{"version":"1.6.0"}`
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  string
+	}{
+		{"minimal", &Entry{Time: ts, Level: InfoLevel, Message: "m"}, head + `}`},
+		{"level", &Entry{Time: ts, Level: ErrorLevel, Message: "m"},
+			`{"@timestamp":"2024-05-06T00:08:09.123Z","log":{"level":"error"},"message":"m","ecs":{"version":"1.6.0"}}`},
+		{"logger and caller", &Entry{Time: ts, Level: InfoLevel, Message: "m", Logger: "api",
+			Caller: &Caller{File: "main.go", Line: 12, Function: "main.run"}},
+			`{"@timestamp":"2024-05-06T00:08:09.123Z","log":{"level":"info","logger":"api",` +
+				`"origin":{"file":{"name":"main.go","line":12},"function":"main.run"}},"message":"m","ecs":{"version":"1.6.0"}}`},
+		{"dotted keys nested", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "http.request.method", Value: "GET"}, {Key: "http.response.status_code", Value: 200}, {Key: "user", Value: "bob"}}},
+			head + `,"http":{"request":{"method":"GET"},"response":{"status_code":200}},"user":"bob"}`},
+		{"group nested", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "http", Value: Object{{Key: "method", Value: "GET"}}}}},
+			head + `,"http":{"method":"GET"}}`},
+		{"clash with ecs field", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "message", Value: "x"}, {Key: "log.level", Value: "y"}}},
+			head + `,"fields":{"message":"x","log":{"level":"y"}}}`},
+		{"clash with scalar parent", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "user", Value: "bob"}, {Key: "user.id", Value: 7}}},
+			head + `,"user":"bob","fields":{"user":{"id":7}}}`},
+		{"clash under fields gets suffix", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "fields.message", Value: "y"}, {Key: "message", Value: "x"}, {Key: "message", Value: "z"}}},
+			head + `,"fields":{"message":"y","message_2":"x","message_3":"z"}}`},
+		{"error", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "error", Value: errors.New("failed")}}},
+			head + `,"error":{"message":"failed","type":"*errors.errorString"}}`},
+		{"error with stack trace", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "err", Value: &stackError{"failed"}}}},
+			head + `,"error":{"message":"failed","type":"*slog.stackError","stack_trace":"failed\nmain.run\n\tmain.go:12"}}`},
+		{"error under other key", &Entry{Time: ts, Level: InfoLevel, Message: "m",
+			Fields: Fields{{Key: "cause", Value: errors.New("failed")}}},
+			head + `,"cause":"failed"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (&ECSEncoder{}).Encode(&buf, tt.entry); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Error("invalid JSON")
+			}
+		})
+	}
+}
+
+func TestECSEncoderDroppedClash(t *testing.T) {
+	var errs []error
+	SetErrorHandler(func(err error) { errs = append(errs, err) })
+	defer SetErrorHandler(nil)
+
+	// `fields` is a scalar, so clashing field can not be placed under it
+	e := &Entry{Level: InfoLevel, Message: "m",
+		Fields: Fields{{Key: "fields", Value: 1}, {Key: "message", Value: "x"}}}
+	var buf bytes.Buffer
+	if err := (&ECSEncoder{}).Encode(&buf, e); err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("reported errors %v, want one clash", errs)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["message"] != "m" || m["fields"] != 1.0 {
+		t.Errorf("encoded %s", buf.String())
+	}
+}
@@ -0,0 +1,211 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Key names of standard entry fields
+const (
+	KeyTime   = "time"
+	KeyLevel  = "level"
+	KeyLogger = "logger"
+	KeyCaller = "caller"
+	KeyMsg    = "msg"
+)
+
+// prefix for field which clashes with standard key
+const fieldClashPrefix = "fields."
+
+func isStdKey(key string) bool {
+	switch key {
+	case KeyTime, KeyLevel, KeyLogger, KeyCaller, KeyMsg:
+		return true
+	}
+	return false
+}
+
+// JSONEncoder writes entry as single line JSON object
+type JSONEncoder struct {
+	// TimestampFormat, default to time.RFC3339Nano, see FormatTimestamp.
+	// Epoch timestamp is written as number.
+	TimestampFormat string
+	// UTC writes timestamp in UTC instead of local time
+	UTC bool
+}
+
+// ContentType of encoded entry
+func (je *JSONEncoder) ContentType() string {
+	return ContentTypeJSON
+}
+
+// Encode entry as JSON
+func (je *JSONEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	tsFormat := je.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = time.RFC3339Nano
+	}
+
+	buf.WriteByte('{')
+	ts, numeric, ok := e.FormatTimestamp(tsFormat, je.UTC)
+	if ok {
+		writeJSONKey(buf, KeyTime, true)
+		if numeric {
+			buf.WriteString(ts)
+		} else {
+			writeJSONString(buf, ts)
+		}
+	}
+	writeJSONKey(buf, KeyLevel, !ok)
+	writeJSONString(buf, e.Level.String())
+	if e.Logger != "" {
+		writeJSONKey(buf, KeyLogger, false)
+		writeJSONString(buf, e.Logger)
+	}
+	if e.Caller != nil {
+		writeJSONKey(buf, KeyCaller, false)
+		writeJSONString(buf, e.Caller.String())
+	}
+	writeJSONKey(buf, KeyMsg, false)
+	writeJSONString(buf, e.Message)
+
+	for _, f := range e.Fields {
+		key := f.Key
+		if isStdKey(key) {
+			key = fieldClashPrefix + key
+		}
+		writeJSONKey(buf, key, false)
+		writeJSONValue(buf, f.Value)
+	}
+	buf.WriteString("}\n")
+
+	return nil
+}
+
+func writeJSONKey(buf *bytes.Buffer, key string, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// JSONValue converts value into something which can be marshaled to JSON
+func JSONValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case Redacted:
+		return JSONValue(ResolveValue(v))
+	case nil, string, bool, json.Marshaler:
+		return v
+	case LogValuer, ObjectMarshaler:
+		return JSONValue(ResolveValue(v))
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	}
+	return val
+}
+
+func writeJSONValue(buf *bytes.Buffer, val interface{}) {
+	b, err := json.Marshal(JSONValue(val))
+	if err != nil {
+		str, _ := AsString(val)
+		writeJSONString(buf, str)
+		return
+	}
+	buf.Write(b)
+}
+
+// LogfmtEncoder writes entry as key=value pairs
+type LogfmtEncoder struct {
+	// TimestampFormat, default to time.RFC3339, see FormatTimestamp
+	TimestampFormat string
+	// UTC writes timestamp in UTC instead of local time
+	UTC bool
+}
+
+// Encode entry as logfmt line
+func (le *LogfmtEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	tsFormat := le.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = time.RFC3339
+	}
+
+	if ts, _, ok := e.FormatTimestamp(tsFormat, le.UTC); ok {
+		writeLogfmt(buf, KeyTime, ts)
+		buf.WriteByte(' ')
+	}
+	writeLogfmt(buf, KeyLevel, e.Level.String())
+	if e.Logger != "" {
+		buf.WriteByte(' ')
+		writeLogfmt(buf, KeyLogger, e.Logger)
+	}
+	if e.Caller != nil {
+		buf.WriteByte(' ')
+		writeLogfmt(buf, KeyCaller, e.Caller.String())
+	}
+	buf.WriteByte(' ')
+	writeLogfmt(buf, KeyMsg, e.Message)
+
+	for _, f := range FlattenFields(e.Fields) {
+		key := f.Key
+		if isStdKey(key) {
+			key = fieldClashPrefix + key
+		}
+		str, _ := AsString(f.Value)
+		buf.WriteByte(' ')
+		writeLogfmt(buf, key, str)
+	}
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+func writeLogfmt(buf *bytes.Buffer, key, val string) {
+	buf.WriteString(logfmtKey(key))
+	buf.WriteByte('=')
+	if needsQuote(val) {
+		buf.WriteString(strconv.Quote(val))
+	} else {
+		buf.WriteString(val)
+	}
+}
+
+// replace characters which are not allowed in logfmt key
+func logfmtKey(key string) string {
+	if key == "" {
+		return UnknownFieldName
+	}
+	if !needsQuote(key) {
+		return key
+	}
+	b := []rune(key)
+	for i, r := range b {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
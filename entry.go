@@ -0,0 +1,118 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Field of a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Caller which writes the entry
+type Caller struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Entry of a log, fields are kept in call-site order
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+	Caller  *Caller
+	Logger  string
+	// Context of the call, e.g. carrying span
+	Context context.Context
+	// Start of elapsed timestamp, set by logger with its own clock
+	// (see HandlerLogger.SetClock). Zero means start of the package clock.
+	Start time.Time
+}
+
+// package path used to skip frames of this module
+var slogPkg = reflect.TypeOf(Entry{}).PkgPath()
+
+// NewEntry creates entry with current time
+func NewEntry(lv Level, msg string, keyVals []interface{}) *Entry {
+	return newEntry(Now(), lv, msg, keyVals)
+}
+
+func newEntry(t time.Time, lv Level, msg string, keyVals []interface{}) *Entry {
+	return &Entry{
+		Time:    t,
+		Level:   lv,
+		Message: msg,
+		Fields:  ResolveFields(ToFields(keyVals)),
+	}
+}
+
+// Field return value of field with given key
+func (e *Entry) Field(key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// SetField replace value of existing field or add new field
+func (e *Entry) SetField(key string, val interface{}) {
+	for i := range e.Fields {
+		if e.Fields[i].Key == key {
+			e.Fields[i].Value = val
+			return
+		}
+	}
+	e.Fields = append(e.Fields, Field{Key: key, Value: val})
+}
+
+// Span return span of entry context
+func (e *Entry) Span() (SpanContext, bool) {
+	return SpanFromContext(e.Context)
+}
+
+// KeyVals return fields as key-value array
+func (e *Entry) KeyVals() []interface{} {
+	return FieldsToKeyVals(e.Fields)
+}
+
+// String return caller as file:line
+func (c *Caller) String() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// CallerOf return first caller outside of this module (including sub packages)
+func CallerOf() *Caller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isSlogFunc(f.Function) {
+			return &Caller{Function: f.Function, File: f.File, Line: f.Line}
+		}
+		if !more {
+			break
+		}
+	}
+	return nil
+}
+
+func isSlogFunc(fn string) bool {
+	// package path ends at the first dot after the last slash
+	pkg := fn
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		pkg = fn[:slash+1+dot]
+	}
+	return pkg == slogPkg || strings.HasPrefix(pkg, slogPkg+"/")
+}
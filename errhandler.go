@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// ErrorHandler receives errors which can not be returned to the caller,
+// e.g. failing hook or failed write to the output.
+type ErrorHandler func(err error)
+
+type errorHandlerHolder struct {
+	fn ErrorHandler
+}
+
+var errHandler atomic.Value
+
+func init() {
+	errHandler.Store(errorHandlerHolder{fn: stderrHandler})
+}
+
+// write error to standard error
+func stderrHandler(err error) {
+	fmt.Fprintf(os.Stderr, "slog: %v\n", err)
+}
+
+// SetErrorHandler replace package error handler, nil restores the default
+// handler which writes to standard error.
+func SetErrorHandler(fn ErrorHandler) {
+	if fn == nil {
+		fn = stderrHandler
+	}
+	errHandler.Store(errorHandlerHolder{fn: fn})
+}
+
+// ReportError passes error to the package error handler
+func ReportError(err error) {
+	if err != nil {
+		errHandler.Load().(errorHandlerHolder).fn(err)
+	}
+}
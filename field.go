@@ -13,6 +13,13 @@ func AsString(val interface{}) (string, bool) {
 		return "", true
 	}
 
+	switch v := val.(type) {
+	case Redacted, LogValuer, ObjectMarshaler:
+		return AsString(ResolveValue(v))
+	case Object:
+		return v.String(), false
+	}
+
 	if str, ok := val.(string); ok {
 		return str, true
 	} else if strp, ok := val.(*string); ok {
@@ -43,6 +50,7 @@ func AsStringQ(val interface{}) string {
 func SimpleFormatter(msg string, keyVals []interface{}, sep string) string {
 	sb := strings.Builder{}
 	sb.WriteString(msg)
+	keyVals = expandFields(keyVals)
 	n := len(keyVals)
 	if n == 0 {
 		return sb.String()
@@ -78,6 +86,7 @@ func SimpleFormatter(msg string, keyVals []interface{}, sep string) string {
 
 // FieldsToMap convert key-value array to map[string]interface{}
 func FieldsToMap(keyVals []interface{}) map[string]interface{} {
+	keyVals = expandFields(keyVals)
 	n := len(keyVals)
 	if n == 0 {
 		return nil
@@ -100,20 +109,30 @@ func FieldsToMap(keyVals []interface{}) map[string]interface{} {
 		} else {
 			key = fmt.Sprintf("%s-%02d", UnknownFieldName, (i)/2+1)
 		}
-		kvMaps[key] = keyVals[i+1]
+		kvMaps[key] = mapValue(keyVals[i+1])
 	}
 
 	// number of args is odd
 	if n != len(keyVals) {
 		key := fmt.Sprintf("%s-%02d", UnknownFieldName, nkv)
-		kvMaps[key] = keyVals[n]
+		kvMaps[key] = mapValue(keyVals[n])
 	}
 
 	return kvMaps
 }
 
+// resolve value, object is converted into map
+func mapValue(val interface{}) interface{} {
+	val = ResolveValue(val)
+	if o, ok := val.(Object); ok {
+		return o.Map()
+	}
+	return val
+}
+
 // SeparateFields into array of keys and array of values
 func SeparateFields(keyVals []interface{}) ([]string, []interface{}) {
+	keyVals = expandFields(keyVals)
 	n := len(keyVals)
 	if n == 0 {
 		return nil, nil
@@ -151,3 +170,30 @@ func SeparateFields(keyVals []interface{}) ([]string, []interface{}) {
 
 	return fields, values
 }
+
+// ToFields convert key-value array into array of field, preserving order
+func ToFields(keyVals []interface{}) []Field {
+	keys, values := SeparateFields(keyVals)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, len(keys))
+	for i, key := range keys {
+		fields[i] = Field{Key: key, Value: values[i]}
+	}
+	return fields
+}
+
+// FieldsToKeyVals convert array of field into key-value array
+func FieldsToKeyVals(fields []Field) []interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	keyVals := make([]interface{}, 0, 2*len(fields))
+	for _, f := range fields {
+		keyVals = append(keyVals, f.Key, f.Value)
+	}
+	return keyVals
+}
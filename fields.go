@@ -0,0 +1,203 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields keeps fields in call-site order
+type Fields []Field
+
+// Get value of the last field with given key
+func (fs Fields) Get(key string) (interface{}, bool) {
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key == key {
+			return fs[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys return keys in order
+func (fs Fields) Keys() []string {
+	keys := make([]string, len(fs))
+	for i, f := range fs {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+// DuplicatePolicy determines how fields with the same key are written
+type DuplicatePolicy int
+
+// Supported duplicate policies
+const (
+	// LastWins keeps the last field at position of the first one
+	LastWins DuplicatePolicy = iota
+	// FirstWins keeps the first field
+	FirstWins
+	// SuffixDuplicates keeps all fields, duplicate keys get suffix _2, _3, ...
+	SuffixDuplicates
+	// ReportDuplicates passes duplicate key to the error handler, the last field wins
+	ReportDuplicates
+)
+
+var duplicatePolicyStrMap = map[string]DuplicatePolicy{
+	"last":   LastWins,
+	"first":  FirstWins,
+	"suffix": SuffixDuplicates,
+	"report": ReportDuplicates,
+}
+
+// Option names of field policy, shared by all loggers
+const (
+	fieldDuplicateKeys = "duplicateKeys"
+	fieldSortFields    = "sortFields"
+)
+
+// OptionDuplicateKeys describes duplicate policy option accepted by loggers
+var OptionDuplicateKeys = OptionSpec{
+	Name:        fieldDuplicateKeys,
+	Type:        StringOption,
+	Default:     "last",
+	Description: "fields with the same key, either last, first, suffix or report",
+	Values:      []string{"last", "first", "suffix", "report"},
+}
+
+// OptionSortFields describes sorted fields option accepted by loggers
+var OptionSortFields = OptionSpec{
+	Name:        fieldSortFields,
+	Type:        BoolOption,
+	Default:     false,
+	Description: "write fields sorted by key",
+}
+
+// FieldPolicy applied to fields of each entry (including nested objects)
+type FieldPolicy struct {
+	Duplicates DuplicatePolicy
+	// Sort fields by key, otherwise fields are kept in call-site order
+	Sort bool
+}
+
+// ParseDuplicatePolicy string, either `last`, `first`, `suffix` or `report`
+func ParseDuplicatePolicy(policy string) (DuplicatePolicy, error) {
+	if p, ok := duplicatePolicyStrMap[strings.ToLower(policy)]; ok {
+		return p, nil
+	}
+	return 0, errors.New("unknown duplicate key policy: " + policy)
+}
+
+// GetFieldPolicy return field policy from duplicateKeys and sortFields options,
+// unknown duplicateKeys value is returned as error
+func (op Options) GetFieldPolicy() (FieldPolicy, error) {
+	p := FieldPolicy{Sort: op.GetBool(fieldSortFields, false)}
+	switch v := op[fieldDuplicateKeys].(type) {
+	case nil:
+	case DuplicatePolicy:
+		p.Duplicates = v
+	case string:
+		d, err := ParseDuplicatePolicy(v)
+		if err != nil {
+			return p, err
+		}
+		p.Duplicates = d
+	default:
+		return p, fmt.Errorf("option %q expects string, got %T", fieldDuplicateKeys, v)
+	}
+	return p, nil
+}
+
+// Apply policy to fields, fields may be modified in place
+func (p FieldPolicy) Apply(fields []Field) []Field {
+	return p.apply(fields, 0)
+}
+
+func (p FieldPolicy) apply(fields []Field, depth int) []Field {
+	if depth < MaxValueDepth {
+		for i, f := range fields {
+			if o, ok := f.Value.(Object); ok {
+				fields[i].Value = Object(p.apply(o, depth+1))
+			}
+		}
+	}
+	if len(fields) > 1 {
+		fields = p.dedupe(fields)
+		if p.Sort {
+			sort.SliceStable(fields, func(i, j int) bool {
+				return fields[i].Key < fields[j].Key
+			})
+		}
+	}
+	return fields
+}
+
+func (p FieldPolicy) dedupe(fields []Field) []Field {
+	var index map[string]int
+	var out []Field
+	for i, f := range fields {
+		pos, dup := -1, false
+		if index != nil {
+			pos, dup = index[f.Key]
+		} else {
+			for j := 0; j < i; j++ {
+				if fields[j].Key == f.Key {
+					pos, dup = j, true
+					break
+				}
+			}
+		}
+		if !dup {
+			if out != nil {
+				index[f.Key] = len(out)
+				out = append(out, f)
+			}
+			continue
+		}
+
+		// copy on the first duplicate, fields may be shared with the caller
+		if out == nil {
+			out = make([]Field, i, len(fields))
+			copy(out, fields[:i])
+			index = make(map[string]int, len(fields))
+			for j, of := range out {
+				if _, ok := index[of.Key]; !ok {
+					index[of.Key] = j
+				}
+			}
+			pos = index[f.Key]
+		}
+		switch p.Duplicates {
+		case FirstWins:
+		case SuffixDuplicates:
+			for n := 2; ; n++ {
+				key := f.Key + "_" + strconv.Itoa(n)
+				if _, ok := index[key]; !ok && !hasKey(fields, key) {
+					index[key] = len(out)
+					out = append(out, Field{Key: key, Value: f.Value})
+					break
+				}
+			}
+		case ReportDuplicates:
+			ReportError(fmt.Errorf("duplicate field %q", f.Key))
+			out[pos].Value = f.Value
+		default:
+			out[pos].Value = f.Value
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func hasKey(fields []Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetFieldPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      Options
+		want    FieldPolicy
+		wantErr bool
+	}{
+		{"default", Options{}, FieldPolicy{}, false},
+		{"first", Options{"duplicateKeys": "first"}, FieldPolicy{Duplicates: FirstWins}, false},
+		{"mixed case", Options{"duplicateKeys": "Suffix", "sortFields": true}, FieldPolicy{Duplicates: SuffixDuplicates, Sort: true}, false},
+		{"typed", Options{"duplicateKeys": ReportDuplicates}, FieldPolicy{Duplicates: ReportDuplicates}, false},
+		{"unknown", Options{"duplicateKeys": "newest"}, FieldPolicy{}, true},
+		{"wrong type", Options{"duplicateKeys": 1}, FieldPolicy{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op.GetFieldPolicy()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFieldPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("GetFieldPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewStdLogger(nil, InfoLevel, Options{"duplicateKeys": "newest"}); err == nil {
+		t.Error("logger created with unknown duplicateKeys")
+	}
+}
+
+func TestFieldPolicyApply(t *testing.T) {
+	f := func(kv ...interface{}) Fields { return Fields(ToFields(kv)) }
+	tests := []struct {
+		name   string
+		policy FieldPolicy
+		in     Fields
+		want   Fields
+	}{
+		{"unique keys kept in order", FieldPolicy{}, f("b", 1, "a", 2), f("b", 1, "a", 2)},
+		{"last wins at first position", FieldPolicy{}, f("a", 1, "b", 2, "a", 3), f("a", 3, "b", 2)},
+		{"first wins", FieldPolicy{Duplicates: FirstWins}, f("a", 1, "b", 2, "a", 3), f("a", 1, "b", 2)},
+		{"suffix", FieldPolicy{Duplicates: SuffixDuplicates}, f("a", 1, "a", 2, "a", 3), f("a", 1, "a_2", 2, "a_3", 3)},
+		{"suffix skips existing key", FieldPolicy{Duplicates: SuffixDuplicates}, f("a", 1, "a_2", 2, "a", 3), f("a", 1, "a_2", 2, "a_3", 3)},
+		{"sorted", FieldPolicy{Sort: true}, f("b", 1, "a", 2, "c", 3), f("a", 2, "b", 1, "c", 3)},
+		{"sorted after dedupe", FieldPolicy{Sort: true}, f("b", 1, "a", 2, "b", 3), f("a", 2, "b", 3)},
+		{"nested object", FieldPolicy{Sort: true}, Fields{{Key: "g", Value: Object(f("y", 1, "x", 2, "y", 3))}},
+			Fields{{Key: "g", Value: Object(f("x", 2, "y", 3))}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append(Fields(nil), tt.in...)
+			if got := Fields(tt.policy.Apply(in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldPolicyReport(t *testing.T) {
+	var errs []error
+	SetErrorHandler(func(err error) { errs = append(errs, err) })
+	defer SetErrorHandler(nil)
+
+	in := ToFields([]interface{}{"a", 1, "a", 2})
+	got := FieldPolicy{Duplicates: ReportDuplicates}.Apply(in)
+	if want := ToFields([]interface{}{"a", 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if len(errs) != 1 || errs[0].Error() != `duplicate field "a"` {
+		t.Errorf("errors %v, want duplicate field", errs)
+	}
+	// fields of the caller are not modified
+	if in[1].Value != 2 || in[0].Value != 1 {
+		t.Errorf("input modified: %v", in)
+	}
+}
+
+func TestFieldsGet(t *testing.T) {
+	fs := Fields(ToFields([]interface{}{"a", 1, "b", 2, "a", 3}))
+	if v, ok := fs.Get("a"); !ok || v != 3 {
+		t.Errorf("Get(a) = %v, %v, want last value", v, ok)
+	}
+	if _, ok := fs.Get("c"); ok {
+		t.Error("Get(c) found")
+	}
+	if got := fs.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "a"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+}
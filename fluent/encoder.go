@@ -0,0 +1,139 @@
+package fluent
+
+import (
+	"bytes"
+
+	"github.com/ipsusila/slog"
+)
+
+// Record keys of standard entry fields
+const (
+	KeyMessage = "message"
+	KeyLevel   = "level"
+	KeyLogger  = "logger"
+	KeyCaller  = "caller"
+)
+
+// default tag if neither tag nor logger name is given
+const defaultTag = "slog"
+
+// prefix for field which clashes with standard key
+const fieldClashPrefix = "fields."
+
+func isStdKey(key string) bool {
+	switch key {
+	case KeyMessage, KeyLevel, KeyLogger, KeyCaller:
+		return true
+	}
+	return false
+}
+
+// Tag of entry, return tag if not empty, otherwise logger name
+func Tag(tag string, e *slog.Entry) string {
+	if tag != "" {
+		return tag
+	}
+	if e.Logger != "" {
+		return e.Logger
+	}
+	return defaultTag
+}
+
+// write time and record of the entry, the elements of [time, record]
+func writeEntry(buf *bytes.Buffer, e *slog.Entry, eventTime bool) {
+	writeTime(buf, e.Time, eventTime)
+	writeRecord(buf, e)
+}
+
+// write entry as record map
+func writeRecord(buf *bytes.Buffer, e *slog.Entry) {
+	n := 2 + len(e.Fields)
+	if e.Logger != "" {
+		n++
+	}
+	if e.Caller != nil {
+		n++
+	}
+
+	writeMapHeader(buf, n)
+	writeString(buf, KeyMessage)
+	writeString(buf, e.Message)
+	writeString(buf, KeyLevel)
+	writeString(buf, e.Level.String())
+	if e.Logger != "" {
+		writeString(buf, KeyLogger)
+		writeString(buf, e.Logger)
+	}
+	if e.Caller != nil {
+		writeString(buf, KeyCaller)
+		writeString(buf, e.Caller.String())
+	}
+	for _, f := range e.Fields {
+		key := f.Key
+		if isStdKey(key) {
+			key = fieldClashPrefix + key
+		}
+		writeString(buf, key)
+		writeValue(buf, f.Value)
+	}
+}
+
+// write option map, chunk is omitted if empty
+func writeOption(buf *bytes.Buffer, size int, chunk string) {
+	if chunk == "" {
+		writeMapHeader(buf, 1)
+	} else {
+		writeMapHeader(buf, 2)
+		writeString(buf, "chunk")
+		writeString(buf, chunk)
+	}
+	writeString(buf, "size")
+	writeInt(buf, int64(size))
+}
+
+// MessageEncoder writes each entry as forward protocol Message mode
+// `[tag, time, record]`, without option.
+type MessageEncoder struct {
+	// Tag of message, default to logger name
+	Tag string
+	// EventTime writes time as EventTime extension with nanosecond precision,
+	// otherwise as unix seconds
+	EventTime bool
+}
+
+// Encode entry
+func (enc *MessageEncoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	var entry bytes.Buffer
+	writeEntry(&entry, e, enc.EventTime)
+	encodeMessage(buf, Tag(enc.Tag, e), entry.Bytes(), "")
+	return nil
+}
+
+// Message mode with option if chunk is not empty, entry is written by writeEntry
+func encodeMessage(buf *bytes.Buffer, tag string, entry []byte, chunk string) {
+	if chunk == "" {
+		writeArrayHeader(buf, 3)
+	} else {
+		writeArrayHeader(buf, 4)
+	}
+	writeString(buf, tag)
+	buf.Write(entry)
+	if chunk != "" {
+		writeOption(buf, 1, chunk)
+	}
+}
+
+// PackedForward mode `[tag, bin(entries), option]`, entries are written by writeEntry
+func encodePacked(buf *bytes.Buffer, tag string, entries [][]byte, chunk string) {
+	var body bytes.Buffer
+	for _, entry := range entries {
+		writeArrayHeader(&body, 2)
+		body.Write(entry)
+	}
+
+	writeArrayHeader(buf, 3)
+	writeString(buf, tag)
+	writeBinHeader(buf, body.Len())
+	buf.Write(body.Bytes())
+	writeOption(buf, len(entries), chunk)
+}
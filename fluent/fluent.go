@@ -0,0 +1,165 @@
+package fluent
+
+import (
+	"io"
+	"strings"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/network"
+)
+
+// Name of fluent logger
+const Name = "fluent"
+
+type fluentConstructor struct{}
+
+const (
+	fieldNetwork       = "network"
+	fieldAddress       = "address"
+	fieldTag           = "tag"
+	fieldMode          = "mode"
+	fieldEventTime     = "eventTime"
+	fieldRequireAck    = "requireAck"
+	fieldAckTimeout    = "ackTimeout"
+	fieldBatchSize     = "batchSize"
+	fieldFlushInterval = "flushInterval"
+	fieldQueueSize     = "queueSize"
+	fieldTLS           = "tls"
+	fieldTLSServerName = "tlsServerName"
+	fieldTLSCAFile     = "tlsCAFile"
+	fieldTLSSkipVerify = "tlsInsecureSkipVerify"
+	fieldDialTimeout   = "dialTimeout"
+	fieldWriteTimeout  = "writeTimeout"
+	fieldMinBackoff    = "minBackoff"
+	fieldMaxBackoff    = "maxBackoff"
+	fieldName          = "name"
+	fieldReportCaller  = "reportCaller"
+	fieldOnError       = "onError"
+)
+
+// options supported by fluent logger
+var fluentSchema = slog.Schema{
+	{Name: fieldNetwork, Type: slog.StringOption, Default: "tcp", Description: "network",
+		Values: []string{"tcp", "tcp4", "tcp6", "unix"}},
+	{Name: fieldAddress, Type: slog.StringOption, Default: DefaultAddress, Description: "forward input address"},
+	{Name: fieldTag, Type: slog.StringOption, Default: "", Description: "tag, default to logger name"},
+	{Name: fieldMode, Type: slog.StringOption, Default: "packed", Description: "forward protocol mode",
+		Values: []string{"packed", "message"}},
+	{Name: fieldEventTime, Type: slog.BoolOption, Default: true, Description: "nanosecond time (EventTime), otherwise unix seconds"},
+	{Name: fieldRequireAck, Type: slog.BoolOption, Default: false, Description: "wait for server acknowledgement"},
+	{Name: fieldAckTimeout, Type: slog.DurationOption, Default: DefaultAckTimeout, Description: "acknowledgement timeout"},
+	{Name: fieldBatchSize, Type: slog.IntOption, Default: DefaultBatchSize, Description: "maximum entries per message"},
+	{Name: fieldFlushInterval, Type: slog.DurationOption, Default: DefaultFlushInterval, Description: "maximum delay before pending entries are sent"},
+	{Name: fieldQueueSize, Type: slog.IntOption, Default: DefaultQueueSize, Description: "maximum pending entries, oldest are dropped"},
+	{Name: fieldTLS, Type: slog.BoolOption, Default: false, Description: "connect using TLS"},
+	{Name: fieldTLSServerName, Type: slog.StringOption, Description: "server name for TLS verification"},
+	{Name: fieldTLSCAFile, Type: slog.StringOption, Description: "PEM file of CA certificates"},
+	{Name: fieldTLSSkipVerify, Type: slog.BoolOption, Default: false, Description: "skip TLS certificate verification"},
+	{Name: fieldDialTimeout, Type: slog.DurationOption, Default: DefaultDialTimeout, Description: "dial timeout"},
+	{Name: fieldWriteTimeout, Type: slog.DurationOption, Default: DefaultWriteTimeout, Description: "write timeout"},
+	{Name: fieldMinBackoff, Type: slog.DurationOption, Default: DefaultMinBackoff, Description: "initial reconnect delay"},
+	{Name: fieldMaxBackoff, Type: slog.DurationOption, Default: DefaultMaxBackoff, Description: "maximum reconnect delay"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name written in each record"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add caller to each record"},
+	{Name: fieldOnError, Type: slog.ErrorHandlerOption, Description: "func(error) receiving connection and write errors"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &fluentConstructor{})
+}
+
+// New creates fluent logger. If w is not nil, entries are written to w
+// in Message mode, otherwise entries are sent to forward input configured by options.
+// Call Flush/Close on the returned *slog.HandlerLogger before the program exits.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := fluentSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	var h slog.Handler
+	if w == nil {
+		cfg, err := ConfigFromOptions(op)
+		if err != nil {
+			return nil, err
+		}
+		h = NewHandler(cfg)
+	} else {
+		h = slog.NewWriterHandler(w, &MessageEncoder{
+			Tag:       op.GetString(fieldTag, ""),
+			EventTime: op.GetBool(fieldEventTime, true),
+		})
+	}
+
+	lg := slog.NewHandlerLogger(h, 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// ConfigFromOptions creates handler configuration from options
+func ConfigFromOptions(op slog.Options) (Config, error) {
+	// connection options are shared with network logger
+	nop := slog.Options{}
+	for k, v := range op {
+		nop[k] = v
+	}
+	nop[fieldAddress] = op.GetString(fieldAddress, DefaultAddress)
+	ncfg, err := network.ConfigFromOptions(nop)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		Network:       ncfg.Network,
+		Address:       ncfg.Address,
+		TLS:           ncfg.TLS,
+		Tag:           op.GetString(fieldTag, ""),
+		Mode:          modeStrMap[strings.ToLower(op.GetString(fieldMode, "packed"))],
+		EventTime:     op.GetBool(fieldEventTime, true),
+		RequireAck:    op.GetBool(fieldRequireAck, false),
+		AckTimeout:    op.GetDuration(fieldAckTimeout, DefaultAckTimeout),
+		BatchSize:     op.GetInt(fieldBatchSize, DefaultBatchSize),
+		FlushInterval: op.GetDuration(fieldFlushInterval, DefaultFlushInterval),
+		QueueSize:     op.GetInt(fieldQueueSize, DefaultQueueSize),
+		DialTimeout:   ncfg.DialTimeout,
+		WriteTimeout:  ncfg.WriteTimeout,
+		MinBackoff:    ncfg.MinBackoff,
+		MaxBackoff:    ncfg.MaxBackoff,
+		OnError:       ncfg.OnError,
+	}, nil
+}
+
+func (c *fluentConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *fluentConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by fluent logger
+func (c *fluentConstructor) Schema() slog.Schema {
+	return fluentSchema
+}
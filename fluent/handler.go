@@ -0,0 +1,403 @@
+package fluent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Mode of forward protocol
+type Mode int
+
+// Supported modes
+const (
+	// PackedForwardMode sends batch of entries with the same tag as single message
+	PackedForwardMode Mode = iota
+	// MessageMode sends each entry as single message
+	MessageMode
+)
+
+var modeStrMap = map[string]Mode{
+	"packed":  PackedForwardMode,
+	"message": MessageMode,
+}
+
+// Default configuration values
+const (
+	DefaultAddress       = "localhost:24224"
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	DefaultQueueSize     = 10000
+	DefaultAckTimeout    = 10 * time.Second
+	DefaultDialTimeout   = 5 * time.Second
+	DefaultWriteTimeout  = 5 * time.Second
+	DefaultMinBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff    = 30 * time.Second
+)
+
+// ErrClosed is returned when handling entry after the handler is closed
+var ErrClosed = errors.New("fluent: handler closed")
+
+// Config of forward handler, zero value uses defaults
+type Config struct {
+	Network string
+	Address string
+	TLS     *tls.Config
+
+	// Tag of messages, default to logger name
+	Tag  string
+	Mode Mode
+	// EventTime writes time with nanosecond precision, otherwise as unix seconds
+	EventTime bool
+	// RequireAck sends chunk option and waits for server acknowledgement
+	RequireAck bool
+	AckTimeout time.Duration
+
+	BatchSize     int
+	FlushInterval time.Duration
+	// QueueSize is maximum entries waiting to be sent, oldest entries are dropped
+	QueueSize int
+
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+
+	// OnError receives connection and write errors, default to slog.ReportError.
+	// It is called without lock held, so it may log to the same sink.
+	OnError slog.ErrorHandler
+}
+
+// Handler sends entries to fluentd/fluent-bit forward input in batch.
+// Entries are encoded when handled, so values may change after logging.
+// Entries which can not be sent are kept and resent after reconnect.
+type Handler struct {
+	cfg Config
+
+	mu      sync.Mutex
+	pending []record
+	dropped int
+	closed  bool
+
+	sendMu sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	buf    bytes.Buffer
+
+	kick chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewHandler creates handler and starts background sender.
+// Connection is established when the first batch is sent.
+func NewHandler(cfg Config) *Handler {
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+	}
+	if cfg.Address == "" {
+		cfg.Address = DefaultAddress
+	}
+	if cfg.AckTimeout <= 0 {
+		cfg.AckTimeout = DefaultAckTimeout
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = DefaultDialTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = slog.ReportError
+	}
+
+	h := &Handler{
+		cfg:  cfg,
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	h.wg.Add(1)
+	go h.run()
+
+	return h
+}
+
+// record is entry encoded by writeEntry with its tag
+type record struct {
+	tag   string
+	entry []byte
+}
+
+// Handle encodes entry and adds it to the pending batch
+func (h *Handler) Handle(e *slog.Entry) error {
+	var buf bytes.Buffer
+	writeEntry(&buf, e, h.cfg.EventTime)
+	rec := record{tag: Tag(h.cfg.Tag, e), entry: buf.Bytes()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrClosed
+	}
+
+	h.pending = append(h.pending, rec)
+	h.trim()
+	if len(h.pending) >= h.cfg.BatchSize {
+		select {
+		case h.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// drop oldest entries, must be called with lock held
+func (h *Handler) trim() {
+	for len(h.pending) > h.cfg.QueueSize {
+		h.pending[0] = record{}
+		h.pending = h.pending[1:]
+		h.dropped++
+	}
+}
+
+func (h *Handler) run() {
+	defer h.wg.Done()
+
+	timer := time.NewTimer(h.cfg.FlushInterval)
+	defer timer.Stop()
+	backoff := h.cfg.MinBackoff
+	var retryAt time.Time
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-timer.C:
+		case <-h.kick:
+			// batch is full, but wait for backoff after failure
+			if time.Now().Before(retryAt) {
+				continue
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		if err := h.Flush(); err != nil {
+			h.cfg.OnError(err)
+			retryAt = time.Now().Add(backoff)
+			timer.Reset(backoff)
+			backoff *= 2
+			if backoff > h.cfg.MaxBackoff {
+				backoff = h.cfg.MaxBackoff
+			}
+			continue
+		}
+		backoff = h.cfg.MinBackoff
+		timer.Reset(h.cfg.FlushInterval)
+	}
+}
+
+// take at most one batch from pending records and number of entries dropped since
+// last call, which is reported by the caller after unlock
+func (h *Handler) takeBatch() ([]record, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dropped := h.dropped
+	h.dropped = 0
+
+	n := len(h.pending)
+	if n > h.cfg.BatchSize {
+		n = h.cfg.BatchSize
+	}
+	if n == 0 {
+		return nil, dropped
+	}
+	batch := make([]record, n)
+	copy(batch, h.pending)
+	for i := 0; i < n; i++ {
+		h.pending[i] = record{}
+	}
+	h.pending = h.pending[n:]
+
+	return batch, dropped
+}
+
+// put records back to the front of pending records
+func (h *Handler) requeue(batch []record) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pending = append(batch, h.pending...)
+	h.trim()
+}
+
+// Flush sends all pending entries. On error, unsent entries are kept.
+func (h *Handler) Flush() error {
+	h.sendMu.Lock()
+	defer h.sendMu.Unlock()
+
+	for {
+		batch, dropped := h.takeBatch()
+		if dropped > 0 {
+			h.cfg.OnError(fmt.Errorf("fluent: %d entries dropped", dropped))
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if n, err := h.send(batch); err != nil {
+			h.closeConn()
+			h.requeue(batch[n:])
+			return err
+		}
+	}
+}
+
+// Close flushes pending entries and closes the connection
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.done)
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	err := h.Flush()
+
+	h.sendMu.Lock()
+	h.closeConn()
+	h.sendMu.Unlock()
+
+	return err
+}
+
+func (h *Handler) closeConn() {
+	if h.conn != nil {
+		h.conn.Close()
+		h.conn = nil
+		h.reader = nil
+	}
+}
+
+func (h *Handler) connect() error {
+	if h.conn != nil {
+		return nil
+	}
+	d := &net.Dialer{Timeout: h.cfg.DialTimeout}
+	var conn net.Conn
+	var err error
+	if h.cfg.TLS != nil {
+		conn, err = tls.DialWithDialer(d, h.cfg.Network, h.cfg.Address, h.cfg.TLS)
+	} else {
+		conn, err = d.Dial(h.cfg.Network, h.cfg.Address)
+	}
+	if err != nil {
+		return fmt.Errorf("fluent: %w", err)
+	}
+	h.conn = conn
+	h.reader = bufio.NewReader(conn)
+	return nil
+}
+
+// send batch, return number of records sent successfully
+func (h *Handler) send(batch []record) (int, error) {
+	if err := h.connect(); err != nil {
+		return 0, err
+	}
+
+	if h.cfg.Mode == MessageMode {
+		for i, r := range batch {
+			h.buf.Reset()
+			chunk := h.chunk()
+			encodeMessage(&h.buf, r.tag, r.entry, chunk)
+			if err := h.write(chunk); err != nil {
+				return i, err
+			}
+		}
+		return len(batch), nil
+	}
+
+	// group consecutive records with the same tag
+	sent := 0
+	for sent < len(batch) {
+		tag := batch[sent].tag
+		n := sent + 1
+		for n < len(batch) && batch[n].tag == tag {
+			n++
+		}
+		entries := make([][]byte, 0, n-sent)
+		for _, r := range batch[sent:n] {
+			entries = append(entries, r.entry)
+		}
+		h.buf.Reset()
+		chunk := h.chunk()
+		encodePacked(&h.buf, tag, entries, chunk)
+		if err := h.write(chunk); err != nil {
+			return sent, err
+		}
+		sent = n
+	}
+	return sent, nil
+}
+
+// unique chunk id if acknowledgement is required
+func (h *Handler) chunk() string {
+	if !h.cfg.RequireAck {
+		return ""
+	}
+	var id [16]byte
+	rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// write encoded message and wait for acknowledgement
+func (h *Handler) write(chunk string) error {
+	h.conn.SetWriteDeadline(time.Now().Add(h.cfg.WriteTimeout))
+	if _, err := h.conn.Write(h.buf.Bytes()); err != nil {
+		return fmt.Errorf("fluent: write: %w", err)
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	h.conn.SetReadDeadline(time.Now().Add(h.cfg.AckTimeout))
+	resp, err := readValue(h.reader)
+	if err != nil {
+		return fmt.Errorf("fluent: ack: %w", err)
+	}
+	m, ok := resp.(map[string]interface{})
+	if !ok || m["ack"] != chunk {
+		return errors.New("fluent: ack: unexpected response")
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package fluent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// event decoded by forward server
+type event struct {
+	tag    string
+	time   time.Time
+	record map[string]interface{}
+}
+
+// forwardServer is local stand-in of fluentd forward input,
+// chunk option is acknowledged
+type forwardServer struct {
+	ln     net.Listener
+	mu     sync.Mutex
+	events []event
+	acks   int
+}
+
+func startForward(t *testing.T, addr string) *forwardServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &forwardServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(t, conn)
+		}
+	}()
+	return s
+}
+
+// freeAddr return local tcp address without listener
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func decodeTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case int64:
+		return time.Unix(t, 0)
+	case uint64:
+		return time.Unix(int64(t), 0)
+	case []byte:
+		// EventTime extension: type, seconds and nanoseconds
+		if len(t) == 9 && t[0] == eventTimeExt {
+			return time.Unix(int64(binary.BigEndian.Uint32(t[1:])), int64(binary.BigEndian.Uint32(t[5:])))
+		}
+	}
+	return time.Time{}
+}
+
+func (s *forwardServer) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		v, err := readValue(r)
+		if err != nil {
+			return
+		}
+		msg, ok := v.([]interface{})
+		if !ok || len(msg) < 3 {
+			t.Errorf("invalid message %v", v)
+			return
+		}
+		tag, _ := msg[0].(string)
+
+		var events []event
+		var option map[string]interface{}
+		if opt, ok := msg[2].(map[string]interface{}); ok && opt["size"] != nil {
+			// PackedForward [tag, bin(entries), option]
+			option = opt
+			er := bufio.NewReader(bytes.NewReader(msg[1].([]byte)))
+			for {
+				ev, err := readValue(er)
+				if err == io.EOF {
+					break
+				}
+				entry, ok := ev.([]interface{})
+				if err != nil || !ok || len(entry) != 2 {
+					t.Errorf("invalid packed entry %v: %v", ev, err)
+					return
+				}
+				rec, _ := entry[1].(map[string]interface{})
+				events = append(events, event{tag, decodeTime(entry[0]), rec})
+			}
+		} else {
+			// Message [tag, time, record, option]
+			rec, _ := msg[2].(map[string]interface{})
+			events = append(events, event{tag, decodeTime(msg[1]), rec})
+			if len(msg) == 4 {
+				option, _ = msg[3].(map[string]interface{})
+			}
+		}
+
+		s.mu.Lock()
+		s.events = append(s.events, events...)
+		s.mu.Unlock()
+
+		if chunk, ok := option["chunk"].(string); ok {
+			var ack bytes.Buffer
+			writeMapHeader(&ack, 1)
+			writeString(&ack, "ack")
+			writeString(&ack, chunk)
+			conn.Write(ack.Bytes())
+			s.mu.Lock()
+			s.acks++
+			s.mu.Unlock()
+		}
+	}
+}
+
+// wait until n events are received
+func (s *forwardServer) wait(t *testing.T, n int) []event {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		events := append([]event(nil), s.events...)
+		s.mu.Unlock()
+		if len(events) >= n || time.Now().After(deadline) {
+			return events
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestForward(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	tests := []struct {
+		name string
+		cfg  Config
+		want time.Time
+	}{
+		{"packed with event time", Config{Mode: PackedForwardMode, EventTime: true}, ts},
+		{"packed with ack", Config{Mode: PackedForwardMode, RequireAck: true}, ts.Truncate(time.Second)},
+		{"message", Config{Mode: MessageMode}, ts.Truncate(time.Second)},
+		{"message with ack and tag", Config{Mode: MessageMode, RequireAck: true, EventTime: true, Tag: "app"}, ts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := startForward(t, "127.0.0.1:0")
+			cfg := tt.cfg
+			cfg.Address = s.ln.Addr().String()
+			cfg.FlushInterval = time.Hour
+			h := NewHandler(cfg)
+			lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+			lg.SetName("api")
+
+			entries := []*slog.Entry{
+				slog.NewEntry(slog.InfoLevel, "started", []interface{}{"port", 8080, "tls", true}),
+				slog.NewEntry(slog.ErrorLevel, "failed", []interface{}{"message", "clash"}),
+				slog.NewEntry(slog.InfoLevel, "query", []interface{}{"ms", 1.5}),
+			}
+			for i, e := range entries {
+				e.Time = ts
+				if i == 2 {
+					e.Logger = "db"
+				}
+				lg.LogEntry(e)
+			}
+			if err := h.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			events := s.wait(t, len(entries))
+			if len(events) != len(entries) {
+				t.Fatalf("received %d events, want %d", len(events), len(entries))
+			}
+			wantTags := []string{"api", "api", "db"}
+			for i, ev := range events {
+				wantTag := wantTags[i]
+				if tt.cfg.Tag != "" {
+					wantTag = tt.cfg.Tag
+				}
+				if ev.tag != wantTag || !ev.time.Equal(tt.want) {
+					t.Errorf("event %d tag %q time %v, want %q %v", i, ev.tag, ev.time, wantTag, tt.want)
+				}
+			}
+			checks := []struct {
+				i   int
+				key string
+				val interface{}
+			}{
+				{0, KeyMessage, "started"}, {0, KeyLevel, "info"}, {0, KeyLogger, "api"},
+				{0, "port", uint64(8080)}, {0, "tls", true},
+				{1, KeyLevel, "error"}, {1, fieldClashPrefix + "message", "clash"},
+				{2, KeyLogger, "db"}, {2, "ms", 1.5},
+			}
+			for _, c := range checks {
+				if got := events[c.i].record[c.key]; got != c.val {
+					t.Errorf("event %d %s = %#v, want %#v", c.i, c.key, got, c.val)
+				}
+			}
+			s.mu.Lock()
+			acks := s.acks
+			s.mu.Unlock()
+			if tt.cfg.RequireAck && acks == 0 {
+				t.Error("no chunk acknowledged")
+			}
+		})
+	}
+}
+
+func TestForwardEncodesAtLogTime(t *testing.T) {
+	s := startForward(t, "127.0.0.1:0")
+	h := NewHandler(Config{Address: s.ln.Addr().String(), BatchSize: 5, FlushInterval: time.Millisecond})
+	lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	// values are modified while the sender runs
+	m := map[string]interface{}{"n": 0}
+	for i := 0; i < 50; i++ {
+		m["n"] = i
+		lg.Infow("msg", "m", m, "i", i)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	events := s.wait(t, 50)
+	if len(events) != 50 {
+		t.Fatalf("received %d events, want 50", len(events))
+	}
+	for _, ev := range events {
+		if got := ev.record["m"].(map[string]interface{})["n"]; got != ev.record["i"] {
+			t.Errorf("map value %v logged with i=%v", got, ev.record["i"])
+		}
+	}
+}
+
+func TestForwardResendAfterFailure(t *testing.T) {
+	addr := freeAddr(t)
+	var mu sync.Mutex
+	var errs []string
+	h := NewHandler(Config{
+		Address:       addr,
+		BatchSize:     2,
+		QueueSize:     2,
+		FlushInterval: time.Hour,
+		MinBackoff:    time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			errs = append(errs, err.Error())
+			mu.Unlock()
+		},
+	})
+	lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	// block sender so the queue overflows
+	h.sendMu.Lock()
+	for i := 0; i < 4; i++ {
+		lg.Info(fmt.Sprint("m", i))
+	}
+	h.sendMu.Unlock()
+	if err := h.Flush(); err == nil {
+		t.Fatal("flush without server succeeded")
+	}
+
+	s := startForward(t, addr)
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	events := s.wait(t, 2)
+	var msgs []string
+	for _, ev := range events {
+		msgs = append(msgs, fmt.Sprint(ev.record[KeyMessage]))
+	}
+	if got := strings.Join(msgs, ","); got != "m2,m3" {
+		t.Errorf("received %s, want m2,m3", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(strings.Join(errs, ";"), "2 entries dropped") {
+		t.Errorf("errors %q, want 2 entries dropped", errs)
+	}
+}
+
+func TestForwardOnErrorLogsBack(t *testing.T) {
+	s := startForward(t, "127.0.0.1:0")
+	var lg *slog.HandlerLogger
+	h := NewHandler(Config{
+		Address:       s.ln.Addr().String(),
+		BatchSize:     2,
+		QueueSize:     2,
+		FlushInterval: time.Hour,
+		OnError:       func(err error) { lg.Warn(err.Error()) },
+	})
+	lg = slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	h.sendMu.Lock()
+	for i := 0; i < 3; i++ {
+		lg.Info(fmt.Sprint("m", i))
+	}
+	h.sendMu.Unlock()
+
+	// error handler logging into the same handler must not deadlock
+	done := make(chan error, 1)
+	go func() { done <- h.Flush() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("flush deadlocked while reporting dropped entries")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, ev := range s.wait(t, 3) {
+		if ev.record[KeyMessage] == "fluent: 1 entries dropped" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("dropped entries report not sent")
+	}
+}
@@ -0,0 +1,347 @@
+package fluent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Minimal MessagePack encoder and decoder used by forward protocol
+
+// extension type of fluentd EventTime
+const eventTimeExt = 0
+
+func writeNil(buf *bytes.Buffer) {
+	buf.WriteByte(0xc0)
+}
+
+func writeBool(buf *bytes.Buffer, v bool) {
+	if v {
+		buf.WriteByte(0xc3)
+	} else {
+		buf.WriteByte(0xc2)
+	}
+}
+
+func writeInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0:
+		writeUint(buf, uint64(v))
+	case v >= -32:
+		buf.WriteByte(byte(v))
+	case v >= math.MinInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(v))
+	case v >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		writeBE(buf, uint64(v), 2)
+	case v >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		writeBE(buf, uint64(v), 4)
+	default:
+		buf.WriteByte(0xd3)
+		writeBE(buf, uint64(v), 8)
+	}
+}
+
+func writeUint(buf *bytes.Buffer, v uint64) {
+	switch {
+	case v <= 0x7f:
+		buf.WriteByte(byte(v))
+	case v <= math.MaxUint8:
+		buf.WriteByte(0xcc)
+		buf.WriteByte(byte(v))
+	case v <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		writeBE(buf, v, 2)
+	case v <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		writeBE(buf, v, 4)
+	default:
+		buf.WriteByte(0xcf)
+		writeBE(buf, v, 8)
+	}
+}
+
+func writeBE(buf *bytes.Buffer, v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		buf.WriteByte(byte(v >> (8 * uint(i))))
+	}
+}
+
+func writeFloat(buf *bytes.Buffer, v float64) {
+	buf.WriteByte(0xcb)
+	writeBE(buf, math.Float64bits(v), 8)
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		writeBE(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdb)
+		writeBE(buf, uint64(n), 4)
+	}
+	buf.WriteString(s)
+}
+
+func writeBinHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xc4)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xc5)
+		writeBE(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xc6)
+		writeBE(buf, uint64(n), 4)
+	}
+}
+
+func writeArrayHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xdc)
+		writeBE(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdd)
+		writeBE(buf, uint64(n), 4)
+	}
+}
+
+func writeMapHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xde)
+		writeBE(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdf)
+		writeBE(buf, uint64(n), 4)
+	}
+}
+
+// write time as EventTime extension (seconds and nanoseconds)
+// or integer unix seconds
+func writeTime(buf *bytes.Buffer, t time.Time, eventTime bool) {
+	if !eventTime {
+		writeInt(buf, t.Unix())
+		return
+	}
+	buf.WriteByte(0xd7)
+	buf.WriteByte(eventTimeExt)
+	writeBE(buf, uint64(t.Unix()), 4)
+	writeBE(buf, uint64(t.Nanosecond()), 4)
+}
+
+// writeValue encodes basic types natively, other values as string
+func writeValue(buf *bytes.Buffer, val interface{}) {
+	switch v := val.(type) {
+	case nil:
+		writeNil(buf)
+	case bool:
+		writeBool(buf, v)
+	case int:
+		writeInt(buf, int64(v))
+	case int8:
+		writeInt(buf, int64(v))
+	case int16:
+		writeInt(buf, int64(v))
+	case int32:
+		writeInt(buf, int64(v))
+	case int64:
+		writeInt(buf, v)
+	case uint:
+		writeUint(buf, uint64(v))
+	case uint8:
+		writeUint(buf, uint64(v))
+	case uint16:
+		writeUint(buf, uint64(v))
+	case uint32:
+		writeUint(buf, uint64(v))
+	case uint64:
+		writeUint(buf, v)
+	case float32:
+		writeFloat(buf, float64(v))
+	case float64:
+		writeFloat(buf, v)
+	case string:
+		writeString(buf, v)
+	case []byte:
+		writeBinHeader(buf, len(v))
+		buf.Write(v)
+	case error:
+		writeString(buf, v.Error())
+	case time.Duration:
+		writeString(buf, v.String())
+	case []interface{}:
+		writeArrayHeader(buf, len(v))
+		for _, item := range v {
+			writeValue(buf, item)
+		}
+	case map[string]interface{}:
+		writeMapHeader(buf, len(v))
+		for k, item := range v {
+			writeString(buf, k)
+			writeValue(buf, item)
+		}
+	case slog.Object:
+		writeMapHeader(buf, len(v))
+		for _, f := range v {
+			writeString(buf, f.Key)
+			writeValue(buf, f.Value)
+		}
+	default:
+		str, _ := slog.AsString(val)
+		writeString(buf, str)
+	}
+}
+
+var errMsgpackType = errors.New("fluent: unsupported msgpack type")
+
+// readValue decodes single value. Maps are decoded as map[string]interface{}
+// (non string keys are formatted), arrays as []interface{}, integers as int64
+// or uint64, and extensions as []byte.
+func readValue(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return readString(r, int(c&0x1f))
+	case c&0xf0 == 0x90:
+		return readArray(r, int(c&0x0f))
+	case c&0xf0 == 0x80:
+		return readMap(r, int(c&0x0f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readBE(r, 1<<(c-0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		v, err := readBE(r, n)
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - 8*n)
+		return int64(v<<shift) >> shift, nil
+	case 0xca:
+		v, err := readBE(r, 4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := readBE(r, 8)
+		return math.Float64frombits(v), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := readBE(r, 1<<(c-0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readBE(r, 1<<(c-0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return readBytes(r, int(n))
+	case 0xdc, 0xdd:
+		n, err := readBE(r, 2<<(c-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return readArray(r, int(n))
+	case 0xde, 0xdf:
+		n, err := readBE(r, 2<<(c-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, int(n))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		// fixext: type byte followed by 1, 2, 4, 8 or 16 bytes
+		return readBytes(r, 1+(1<<(c-0xd4)))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := readBE(r, 1<<(c-0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return readBytes(r, 1+int(n))
+	}
+	return nil, fmt.Errorf("%w 0x%02x", errMsgpackType, c)
+}
+
+func readBE(r *bufio.Reader, n int) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[8-n:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+func readBytes(r *bufio.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+func readString(r *bufio.Reader, n int) (string, error) {
+	b, err := readBytes(r, n)
+	return string(b), err
+}
+
+func readArray(r *bufio.Reader, n int) ([]interface{}, error) {
+	arr := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func readMap(r *bufio.Reader, n int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := slog.AsString(k)
+		m[key] = v
+	}
+	return m, nil
+}
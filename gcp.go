@@ -0,0 +1,340 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Special keys of Cloud Logging structured log
+const (
+	gcpKeySourceLocation = "logging.googleapis.com/sourceLocation"
+	gcpKeyTrace          = "logging.googleapis.com/trace"
+	gcpKeySpanID         = "logging.googleapis.com/spanId"
+	gcpKeyTraceSampled   = "logging.googleapis.com/trace_sampled"
+	gcpKeyHTTPRequest    = "httpRequest"
+)
+
+func isGCPKey(key string) bool {
+	switch key {
+	case "severity", "message", "time", KeyLogger, gcpKeySourceLocation,
+		gcpKeyTrace, gcpKeySpanID, gcpKeyTraceSampled, gcpKeyHTTPRequest:
+		return true
+	}
+	return false
+}
+
+// HTTPRequest written as Cloud Logging httpRequest object, empty fields are omitted
+type HTTPRequest struct {
+	RequestMethod string        `json:"requestMethod,omitempty"`
+	RequestURL    string        `json:"requestUrl,omitempty"`
+	RequestSize   int64         `json:"requestSize,string,omitempty"`
+	Status        int           `json:"status,omitempty"`
+	ResponseSize  int64         `json:"responseSize,string,omitempty"`
+	UserAgent     string        `json:"userAgent,omitempty"`
+	RemoteIP      string        `json:"remoteIp,omitempty"`
+	ServerIP      string        `json:"serverIp,omitempty"`
+	Referer       string        `json:"referer,omitempty"`
+	Latency       time.Duration `json:"-"`
+	Protocol      string        `json:"protocol,omitempty"`
+}
+
+// NewHTTPRequest creates request information from *http.Request
+func NewHTTPRequest(r *http.Request) *HTTPRequest {
+	req := &HTTPRequest{
+		RequestMethod: r.Method,
+		RequestURL:    r.URL.String(),
+		UserAgent:     r.UserAgent(),
+		RemoteIP:      r.RemoteAddr,
+		Referer:       r.Referer(),
+		Protocol:      r.Proto,
+	}
+	if r.ContentLength > 0 {
+		req.RequestSize = r.ContentLength
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		req.RemoteIP = host
+	}
+	return req
+}
+
+// CloudSeverity maps level to Cloud Logging severity,
+// user defined level between info and warn is mapped to NOTICE
+func CloudSeverity(lv Level) string {
+	sev := lv.Severity()
+	switch {
+	case sev >= PanicSeverity:
+		return "ALERT"
+	case sev >= FatalSeverity:
+		return "CRITICAL"
+	case sev >= ErrorSeverity:
+		return "ERROR"
+	case sev >= WarnSeverity:
+		return "WARNING"
+	case sev > InfoSeverity:
+		return "NOTICE"
+	case sev >= InfoSeverity:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// CloudLoggingEncoder writes entry as JSON which is parsed by Google Cloud Logging
+// (e.g. stdout of GKE or Cloud Run). Field with HTTPRequest, *HTTPRequest or
+// *http.Request value is written as httpRequest. Well-known plain keys
+// (method, url, status, latency, userAgent, remoteIp, referer, protocol,
+// requestSize, responseSize) are merged into httpRequest when the entry has a
+// request value or a method or url key, otherwise they stay ordinary fields.
+type CloudLoggingEncoder struct {
+	// ProjectID used to write trace as projects/ID/traces/TRACE_ID
+	ProjectID string
+}
+
+// NewCloudLoggingEncoder creates encoder using GOOGLE_CLOUD_PROJECT environment as project ID
+func NewCloudLoggingEncoder() *CloudLoggingEncoder {
+	return &CloudLoggingEncoder{ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT")}
+}
+
+// ContentType of encoded entry
+func (enc *CloudLoggingEncoder) ContentType() string {
+	return ContentTypeJSON
+}
+
+// Encode entry
+func (enc *CloudLoggingEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	buf.WriteByte('{')
+	writeJSONKey(buf, "severity", true)
+	writeJSONString(buf, CloudSeverity(e.Level))
+	writeJSONKey(buf, "message", false)
+	writeJSONString(buf, e.Message)
+	writeJSONKey(buf, "time", false)
+	writeJSONString(buf, e.Time.UTC().Format(time.RFC3339Nano))
+	if e.Logger != "" {
+		writeJSONKey(buf, KeyLogger, false)
+		writeJSONString(buf, e.Logger)
+	}
+	if e.Caller != nil {
+		writeJSONKey(buf, gcpKeySourceLocation, false)
+		buf.WriteByte('{')
+		writeJSONKey(buf, "file", true)
+		writeJSONString(buf, e.Caller.File)
+		writeJSONKey(buf, "line", false)
+		writeJSONString(buf, strconv.Itoa(e.Caller.Line))
+		if e.Caller.Function != "" {
+			writeJSONKey(buf, "function", false)
+			writeJSONString(buf, e.Caller.Function)
+		}
+		buf.WriteByte('}')
+	}
+	if sc, ok := e.Span(); ok {
+		trace := sc.TraceID
+		if enc.ProjectID != "" {
+			trace = "projects/" + enc.ProjectID + "/traces/" + trace
+		}
+		writeJSONKey(buf, gcpKeyTrace, false)
+		writeJSONString(buf, trace)
+		writeJSONKey(buf, gcpKeySpanID, false)
+		writeJSONString(buf, sc.SpanID)
+		writeJSONKey(buf, gcpKeyTraceSampled, false)
+		writeJSONValue(buf, sc.Sampled)
+	}
+
+	req, used := httpRequestOf(e.Fields)
+	for i, f := range e.Fields {
+		if used != nil && used[i] {
+			if req != nil {
+				writeJSONKey(buf, gcpKeyHTTPRequest, false)
+				writeHTTPRequest(buf, req)
+				req = nil
+			}
+			continue
+		}
+		key := f.Key
+		if isGCPKey(key) {
+			key = fieldClashPrefix + key
+		}
+		writeJSONKey(buf, key, false)
+		writeJSONValue(buf, f.Value)
+	}
+	buf.WriteString("}\n")
+
+	return nil
+}
+
+func toHTTPRequest(val interface{}) *HTTPRequest {
+	switch v := val.(type) {
+	case HTTPRequest:
+		return &v
+	case *HTTPRequest:
+		if v != nil {
+			req := *v
+			return &req
+		}
+	case *http.Request:
+		if v != nil {
+			return NewHTTPRequest(v)
+		}
+	}
+	return nil
+}
+
+// gcpHTTPKeys maps well-known plain keys to httpRequest fields,
+// setter returns false if value can not be converted
+var gcpHTTPKeys = map[string]func(req *HTTPRequest, val interface{}) bool{
+	"method":        func(r *HTTPRequest, v interface{}) bool { return setString(&r.RequestMethod, v) },
+	"url":           func(r *HTTPRequest, v interface{}) bool { return setString(&r.RequestURL, v) },
+	"status":        func(r *HTTPRequest, v interface{}) bool { return setStatus(&r.Status, v) },
+	"statusCode":    func(r *HTTPRequest, v interface{}) bool { return setStatus(&r.Status, v) },
+	"status_code":   func(r *HTTPRequest, v interface{}) bool { return setStatus(&r.Status, v) },
+	"latency":       func(r *HTTPRequest, v interface{}) bool { return setLatency(&r.Latency, v) },
+	"userAgent":     func(r *HTTPRequest, v interface{}) bool { return setString(&r.UserAgent, v) },
+	"user_agent":    func(r *HTTPRequest, v interface{}) bool { return setString(&r.UserAgent, v) },
+	"remoteIp":      func(r *HTTPRequest, v interface{}) bool { return setString(&r.RemoteIP, v) },
+	"remote_ip":     func(r *HTTPRequest, v interface{}) bool { return setString(&r.RemoteIP, v) },
+	"referer":       func(r *HTTPRequest, v interface{}) bool { return setString(&r.Referer, v) },
+	"protocol":      func(r *HTTPRequest, v interface{}) bool { return setString(&r.Protocol, v) },
+	"requestSize":   func(r *HTTPRequest, v interface{}) bool { return setSize(&r.RequestSize, v) },
+	"request_size":  func(r *HTTPRequest, v interface{}) bool { return setSize(&r.RequestSize, v) },
+	"responseSize":  func(r *HTTPRequest, v interface{}) bool { return setSize(&r.ResponseSize, v) },
+	"response_size": func(r *HTTPRequest, v interface{}) bool { return setSize(&r.ResponseSize, v) },
+}
+
+// httpRequestOf collects httpRequest from request value and plain keys,
+// used marks the fields written as httpRequest. Plain keys are only
+// collected when entry has request value, method or url, so unrelated
+// status field is kept as is. Plain value does not override request value.
+func httpRequestOf(fields Fields) (*HTTPRequest, []bool) {
+	var req *HTTPRequest
+	var used []bool
+	hasPlain := false
+	for i, f := range fields {
+		if req == nil {
+			if req = toHTTPRequest(f.Value); req != nil {
+				used = make([]bool, len(fields))
+				used[i] = true
+				continue
+			}
+		}
+		if f.Key == "method" || f.Key == "url" {
+			hasPlain = true
+		}
+	}
+	if req == nil && !hasPlain {
+		return nil, nil
+	}
+	if req == nil {
+		req = &HTTPRequest{}
+		used = make([]bool, len(fields))
+	}
+
+	var plain HTTPRequest
+	for i, f := range fields {
+		if set, ok := gcpHTTPKeys[f.Key]; ok && !used[i] && set(&plain, f.Value) {
+			used[i] = true
+		}
+	}
+	mergeHTTPRequest(req, &plain)
+	return req, used
+}
+
+// mergeHTTPRequest fills empty fields of req from src
+func mergeHTTPRequest(req, src *HTTPRequest) {
+	fill := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	fill(&req.RequestMethod, src.RequestMethod)
+	fill(&req.RequestURL, src.RequestURL)
+	fill(&req.UserAgent, src.UserAgent)
+	fill(&req.RemoteIP, src.RemoteIP)
+	fill(&req.ServerIP, src.ServerIP)
+	fill(&req.Referer, src.Referer)
+	fill(&req.Protocol, src.Protocol)
+	if req.Status == 0 {
+		req.Status = src.Status
+	}
+	if req.RequestSize == 0 {
+		req.RequestSize = src.RequestSize
+	}
+	if req.ResponseSize == 0 {
+		req.ResponseSize = src.ResponseSize
+	}
+	if req.Latency == 0 {
+		req.Latency = src.Latency
+	}
+}
+
+func setString(dst *string, val interface{}) bool {
+	switch v := val.(type) {
+	case string:
+		*dst = v
+	case fmt.Stringer:
+		*dst = v.String()
+	default:
+		return false
+	}
+	return true
+}
+
+func setStatus(dst *int, val interface{}) bool {
+	n, ok := toInt(val)
+	if !ok {
+		if s, isStr := val.(string); isStr {
+			n, ok = parseInt(s)
+		}
+	}
+	if !ok || n < 100 || n > 999 {
+		return false
+	}
+	*dst = int(n)
+	return true
+}
+
+func setSize(dst *int64, val interface{}) bool {
+	n, ok := toInt(val)
+	if !ok || n < 0 {
+		return false
+	}
+	*dst = n
+	return true
+}
+
+func setLatency(dst *time.Duration, val interface{}) bool {
+	switch v := val.(type) {
+	case time.Duration:
+		*dst = v
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return false
+		}
+		*dst = d
+	default:
+		return false
+	}
+	return true
+}
+
+func parseInt(s string) (int64, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+func writeHTTPRequest(buf *bytes.Buffer, req *HTTPRequest) {
+	if req.Latency <= 0 {
+		writeJSONValue(buf, req)
+		return
+	}
+
+	// latency is written as duration string in seconds, e.g. "0.25s"
+	writeJSONValue(buf, struct {
+		*HTTPRequest
+		Latency string `json:"latency"`
+	}{req, strconv.FormatFloat(req.Latency.Seconds(), 'f', -1, 64) + "s"})
+}
@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCloudSeverity(t *testing.T) {
+	tests := []struct {
+		lv   Level
+		want string
+	}{
+		{TraceLevel, "DEBUG"},
+		{DebugLevel, "DEBUG"},
+		{InfoLevel, "INFO"},
+		{WarnLevel, "WARNING"},
+		{ErrorLevel, "ERROR"},
+		{FatalLevel, "CRITICAL"},
+		{PanicLevel, "ALERT"},
+	}
+	for _, tt := range tests {
+		if got := CloudSeverity(tt.lv); got != tt.want {
+			t.Errorf("CloudSeverity(%v) = %s, want %s", tt.lv, got, tt.want)
+		}
+	}
+}
+
+func TestCloudLoggingEncoder(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	head := `{"severity":"INFO","message":"req","time":"2024-05-06T07:08:09Z"`
+	r := httptest.NewRequest("POST", "http://example.com/api", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("User-Agent", "curl")
+
+	tests := []struct {
+		name   string
+		fields []interface{}
+		want   string
+	}{
+		{
+			"plain keys",
+			[]interface{}{"method", "GET", "url", "/a", "status", 200, "latency", 250 * time.Millisecond,
+				"user_agent", "curl", "remoteIp", "10.0.0.1", "responseSize", 12, "n", 1},
+			head + `,"httpRequest":{"requestMethod":"GET","requestUrl":"/a","status":200,"responseSize":"12",` +
+				`"userAgent":"curl","remoteIp":"10.0.0.1","latency":"0.25s"},"n":1}`,
+		},
+		{
+			"plain string values",
+			[]interface{}{"url", "/a", "status", "404", "latency", "1.5s"},
+			head + `,"httpRequest":{"requestUrl":"/a","status":404,"latency":"1.5s"}}`,
+		},
+		{
+			"plain keys without method or url",
+			[]interface{}{"status", "ok", "latency", time.Second},
+			head + `,"status":"ok","latency":"1s"}`,
+		},
+		{
+			"invalid plain value kept",
+			[]interface{}{"method", "GET", "status", "ok"},
+			head + `,"httpRequest":{"requestMethod":"GET"},"status":"ok"}`,
+		},
+		{
+			"request merged with plain keys",
+			[]interface{}{"n", 1, "req", r, "method", "PUT", "status", 201, "latency", time.Second},
+			head + `,"n":1,"httpRequest":{"requestMethod":"POST","requestUrl":"http://example.com/api","status":201,` +
+				`"userAgent":"curl","remoteIp":"10.0.0.1","protocol":"HTTP/1.1","latency":"1s"}}`,
+		},
+		{
+			"typed request",
+			[]interface{}{"req", &HTTPRequest{RequestMethod: "GET", Status: 500}, "other", HTTPRequest{Status: 200}},
+			head + `,"httpRequest":{"requestMethod":"GET","status":500},"other":{"status":200}}`,
+		},
+		{
+			"clashing keys",
+			[]interface{}{"message", "m", "severity", 1},
+			head + `,"fields.message":"m","fields.severity":1}`,
+		},
+	}
+	enc := &CloudLoggingEncoder{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEntry(InfoLevel, "req", tt.fields)
+			e.Time = ts
+			var buf bytes.Buffer
+			if err := enc.Encode(&buf, e); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudLoggingEncoderTrace(t *testing.T) {
+	e := NewEntry(ErrorLevel, "failed", nil)
+	e.Time = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	e.Logger = "api"
+	e.Caller = &Caller{File: "main.go", Line: 10, Function: "main.run"}
+	e.Context = ContextWithSpan(context.Background(), SpanContext{TraceID: "abc", SpanID: "def", Sampled: true})
+
+	var buf bytes.Buffer
+	enc := &CloudLoggingEncoder{ProjectID: "proj"}
+	if err := enc.Encode(&buf, e); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"severity":"ERROR","message":"failed","time":"2024-05-06T07:08:09Z","logger":"api",` +
+		`"logging.googleapis.com/sourceLocation":{"file":"main.go","line":"10","function":"main.run"},` +
+		`"logging.googleapis.com/trace":"projects/proj/traces/abc","logging.googleapis.com/spanId":"def",` +
+		`"logging.googleapis.com/trace_sampled":true}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+package gelf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/syslog"
+)
+
+// GELF version written in each message
+const Version = "1.1"
+
+// Encoder formats entry as GELF 1.1 JSON message without terminator.
+// Fields are written as additional fields with `_` prefix.
+type Encoder struct {
+	Host string
+}
+
+// ContentType of encoded entry
+func (enc *Encoder) ContentType() string {
+	return slog.ContentTypeJSON
+}
+
+// Encode entry
+func (enc *Encoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	host := enc.Host
+	if host == "" {
+		host = "localhost"
+	}
+	short, full := e.Message, ""
+	if i := strings.IndexByte(short, '\n'); i >= 0 {
+		short, full = short[:i], e.Message
+	}
+	if short == "" {
+		short = "-"
+	}
+
+	buf.WriteString(`{"version":"` + Version + `","host":`)
+	writeString(buf, host)
+	buf.WriteString(`,"short_message":`)
+	writeString(buf, short)
+	if full != "" {
+		buf.WriteString(`,"full_message":`)
+		writeString(buf, full)
+	}
+	ns := e.Time.UnixNano()
+	buf.WriteString(`,"timestamp":`)
+	buf.WriteString(strconv.FormatInt(ns/1e9, 10))
+	buf.WriteByte('.')
+	buf.WriteString(strconv.FormatInt(1000+(ns%1e9)/1e6, 10)[1:])
+	buf.WriteString(`,"level":`)
+	buf.WriteString(strconv.Itoa(syslog.Severity(e.Level)))
+
+	if e.Logger != "" {
+		buf.WriteString(`,"_logger":`)
+		writeString(buf, e.Logger)
+	}
+	if e.Caller != nil {
+		buf.WriteString(`,"_file":`)
+		writeString(buf, e.Caller.File)
+		buf.WriteString(`,"_line":`)
+		buf.WriteString(strconv.Itoa(e.Caller.Line))
+		if e.Caller.Function != "" {
+			buf.WriteString(`,"_function":`)
+			writeString(buf, e.Caller.Function)
+		}
+	}
+	for _, f := range slog.FlattenFields(e.Fields) {
+		buf.WriteByte(',')
+		writeString(buf, FieldName(f.Key))
+		buf.WriteByte(':')
+		writeValue(buf, f.Value)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// FieldName converts key into additional field name.
+// Characters other than letter, digit, underscore, dash and dot are replaced
+// with underscore. Reserved `_id` and names clashing with entry fields
+// are prefixed with `_field`.
+func FieldName(key string) string {
+	b := make([]byte, 0, len(key)+1)
+	b = append(b, '_')
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '-', c == '.':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+	name := string(b)
+	switch name {
+	case "_", "_id", "_logger", "_file", "_line", "_function":
+		return "_field" + name
+	}
+	return name
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// additional field value is either number or string
+func writeValue(buf *bytes.Buffer, val interface{}) {
+	switch v := val.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		if b, err := json.Marshal(v); err == nil {
+			buf.Write(b)
+			return
+		}
+	}
+	str, _ := slog.AsString(val)
+	writeString(buf, str)
+}
@@ -0,0 +1,81 @@
+package gelf
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user", "_user"},
+		{"http.status-code", "_http.status-code"},
+		{"my key!", "_my_key_"},
+		{"", "_field_"},
+		{"id", "_field_id"},
+		{"logger", "_field_logger"},
+		{"file", "_field_file"},
+		{"line", "_field_line"},
+		{"function", "_field_function"},
+		{"_id", "__id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := FieldName(tt.key); got != tt.want {
+				t.Errorf("FieldName(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	tests := []struct {
+		name  string
+		entry *slog.Entry
+		want  string
+	}{
+		{"minimal", &slog.Entry{Time: ts, Level: slog.InfoLevel, Message: "started"},
+			`{"version":"1.1","host":"web-1","short_message":"started","timestamp":1714979289.123,"level":6}`},
+		{"empty message", &slog.Entry{Time: ts, Level: slog.WarnLevel},
+			`{"version":"1.1","host":"web-1","short_message":"-","timestamp":1714979289.123,"level":4}`},
+		{"multi-line message", &slog.Entry{Time: ts, Level: slog.ErrorLevel, Message: "failed\ndetail"},
+			`{"version":"1.1","host":"web-1","short_message":"failed","full_message":"failed\ndetail","timestamp":1714979289.123,"level":3}`},
+		{"logger and caller", &slog.Entry{Time: ts, Level: slog.InfoLevel, Message: "m", Logger: "api",
+			Caller: &slog.Caller{File: "main.go", Line: 12, Function: "main.run"}},
+			`{"version":"1.1","host":"web-1","short_message":"m","timestamp":1714979289.123,"level":6,` +
+				`"_logger":"api","_file":"main.go","_line":12,"_function":"main.run"}`},
+		{"additional fields", &slog.Entry{Time: ts, Level: slog.InfoLevel, Message: "m",
+			Fields: slog.Fields{{Key: "status", Value: 200}, {Key: "ms", Value: 1.5}, {Key: "path", Value: "/"}, {Key: "ok", Value: true}}},
+			`{"version":"1.1","host":"web-1","short_message":"m","timestamp":1714979289.123,"level":6,` +
+				`"_status":200,"_ms":1.5,"_path":"/","_ok":"true"}`},
+		{"reserved and clashing keys", &slog.Entry{Time: ts, Level: slog.InfoLevel, Message: "m", Logger: "api",
+			Fields: slog.Fields{{Key: "id", Value: 7}, {Key: "logger", Value: "other"}, {Key: "user name", Value: "bob"}}},
+			`{"version":"1.1","host":"web-1","short_message":"m","timestamp":1714979289.123,"level":6,"_logger":"api",` +
+				`"_field_id":7,"_field_logger":"other","_user_name":"bob"}`},
+		{"group flattened", &slog.Entry{Time: ts, Level: slog.InfoLevel, Message: "m",
+			Fields: slog.Fields{{Key: "req", Value: slog.Object{{Key: "id", Value: "r1"}}}}},
+			`{"version":"1.1","host":"web-1","short_message":"m","timestamp":1714979289.123,"level":6,"_req.id":"r1"}`},
+	}
+	enc := &Encoder{Host: "web-1"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := enc.Encode(&buf, tt.entry); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Error("invalid JSON")
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package gelf
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/network"
+)
+
+// Name of GELF logger
+const Name = "gelf"
+
+type gelfConstructor struct{}
+
+const (
+	fieldNetwork       = "network"
+	fieldAddress       = "address"
+	fieldCompression   = "compression"
+	fieldChunkSize     = "chunkSize"
+	fieldHost          = "host"
+	fieldTLS           = "tls"
+	fieldTLSServerName = "tlsServerName"
+	fieldTLSCAFile     = "tlsCAFile"
+	fieldTLSSkipVerify = "tlsInsecureSkipVerify"
+	fieldDialTimeout   = "dialTimeout"
+	fieldWriteTimeout  = "writeTimeout"
+	fieldMinBackoff    = "minBackoff"
+	fieldMaxBackoff    = "maxBackoff"
+	fieldBufferSize    = "bufferSize"
+	fieldName          = "name"
+	fieldReportCaller  = "reportCaller"
+	fieldOnError       = "onError"
+	defaultAddress     = "localhost:12201"
+	defaultCompression = "gzip"
+	fieldFraming       = "framing"
+	nullFraming        = "null"
+)
+
+// options supported by GELF logger
+var gelfSchema = slog.Schema{
+	{Name: fieldNetwork, Type: slog.StringOption, Default: "udp", Description: "network",
+		Values: []string{"udp", "udp4", "udp6", "tcp", "tcp4", "tcp6"}},
+	{Name: fieldAddress, Type: slog.StringOption, Default: defaultAddress, Description: "Graylog input address (host:port)"},
+	{Name: fieldCompression, Type: slog.StringOption, Default: defaultCompression, Description: "UDP message compression",
+		Values: []string{"none", "gzip", "zlib"}},
+	{Name: fieldChunkSize, Type: slog.IntOption, Default: DefaultChunkSize, Description: "maximum UDP datagram size (512 to 65507)"},
+	{Name: fieldHost, Type: slog.StringOption, Description: "host, default to os.Hostname"},
+	{Name: fieldTLS, Type: slog.BoolOption, Default: false, Description: "connect using TLS (tcp)"},
+	{Name: fieldTLSServerName, Type: slog.StringOption, Description: "server name for TLS verification"},
+	{Name: fieldTLSCAFile, Type: slog.StringOption, Description: "PEM file of CA certificates"},
+	{Name: fieldTLSSkipVerify, Type: slog.BoolOption, Default: false, Description: "skip TLS certificate verification"},
+	{Name: fieldDialTimeout, Type: slog.DurationOption, Default: network.DefaultDialTimeout, Description: "dial timeout (tcp)"},
+	{Name: fieldWriteTimeout, Type: slog.DurationOption, Default: network.DefaultWriteTimeout, Description: "write timeout (tcp)"},
+	{Name: fieldMinBackoff, Type: slog.DurationOption, Default: network.DefaultMinBackoff, Description: "initial reconnect delay (tcp)"},
+	{Name: fieldMaxBackoff, Type: slog.DurationOption, Default: network.DefaultMaxBackoff, Description: "maximum reconnect delay (tcp)"},
+	{Name: fieldBufferSize, Type: slog.IntOption, Default: network.DefaultBufferSize, Description: "maximum bytes buffered while disconnected (tcp)"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name, written as _logger"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "write _file, _line and _function"},
+	{Name: fieldOnError, Type: slog.ErrorHandlerOption, Description: "func(error) receiving connection and write errors (tcp)"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+// options passed to network writer of TCP transport
+var networkOptions = []string{
+	fieldTLS, fieldTLSServerName, fieldTLSCAFile, fieldTLSSkipVerify,
+	fieldDialTimeout, fieldWriteTimeout, fieldMinBackoff, fieldMaxBackoff, fieldBufferSize, fieldOnError,
+}
+
+func init() {
+	slog.Register(Name, &gelfConstructor{})
+}
+
+// New creates GELF logger. If w is not nil, messages are written to w
+// one per line, otherwise messages are sent to Graylog input configured by options.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := gelfSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		var err error
+		if w, err = NewWriter(op); err != nil {
+			return nil, err
+		}
+	} else {
+		w = &lineWriter{w: w}
+	}
+
+	lg := slog.NewHandlerLogger(slog.NewWriterHandler(w, NewEncoder(op)), 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// NewEncoder creates GELF encoder from options
+func NewEncoder(op slog.Options) *Encoder {
+	host := op.GetString(fieldHost, "")
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	return &Encoder{Host: host}
+}
+
+// NewWriter creates UDP writer or null delimited TCP network writer from options
+func NewWriter(op slog.Options) (io.Writer, error) {
+	nw := strings.ToLower(op.GetString(fieldNetwork, "udp"))
+	address := op.GetString(fieldAddress, defaultAddress)
+	if strings.HasPrefix(nw, "udp") {
+		c := compressionStrMap[strings.ToLower(op.GetString(fieldCompression, defaultCompression))]
+		return NewUDPWriter(nw, address, c, op.GetInt(fieldChunkSize, DefaultChunkSize))
+	}
+
+	// TCP does not support compression, messages are terminated by null byte
+	nop := slog.Options{}
+	for _, k := range networkOptions {
+		if v, ok := op[k]; ok {
+			nop[k] = v
+		}
+	}
+	nop[fieldNetwork] = nw
+	nop[fieldAddress] = address
+	nop[fieldFraming] = nullFraming
+	cfg, err := network.ConfigFromOptions(nop)
+	if err != nil {
+		return nil, err
+	}
+	return network.NewWriter(cfg), nil
+}
+
+// write each message as single line
+type lineWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.buf.Reset()
+	lw.buf.Write(p)
+	lw.buf.WriteByte('\n')
+	if _, err := lw.w.Write(lw.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *gelfConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *gelfConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by GELF logger
+func (c *gelfConstructor) Schema() slog.Schema {
+	return gelfSchema
+}
@@ -0,0 +1,59 @@
+package gelf
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+func TestTCPNullFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// UDP only options are not passed to the network writer
+	lg, err := slog.NewWithOptions(Name, nil, slog.InfoLevel, slog.Options{
+		"network":     "TCP",
+		"address":     ln.Addr().String(),
+		"host":        "web-1",
+		"compression": "zlib",
+		"chunkSize":   100000,
+		"name":        "api",
+		"onError":     func(error) {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.(*slog.HandlerLogger).Close()
+
+	ln.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	lg.Info("first")
+	lg.Infow("second\nline", "n", 1)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	for _, want := range []string{"first", "second"} {
+		msg, err := r.ReadBytes(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(msg[:len(msg)-1], &m); err != nil {
+			t.Fatalf("message %q: %v", msg, err)
+		}
+		if m["short_message"] != want || m["host"] != "web-1" || m["_logger"] != "api" {
+			t.Errorf("message %v, want short_message %q", m, want)
+		}
+	}
+}
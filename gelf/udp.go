@@ -0,0 +1,149 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Compression of UDP message
+type Compression int
+
+// Supported compression
+const (
+	NoCompression Compression = iota
+	GzipCompression
+	ZlibCompression
+)
+
+var compressionStrMap = map[string]Compression{
+	"none": NoCompression,
+	"gzip": GzipCompression,
+	"zlib": ZlibCompression,
+}
+
+// Chunk size and limit, MaxChunkSize is the largest UDP payload over IPv4
+const (
+	DefaultChunkSize = 1420
+	MinChunkSize     = 512
+	MaxChunkSize     = 65507
+	MaxChunks        = 128
+	chunkHeaderSize  = 12
+)
+
+var chunkMagic = []byte{0x1e, 0x0f}
+
+// UDPWriter writes each message as GELF UDP datagram.
+// Message larger than chunk size is split into GELF chunks.
+type UDPWriter struct {
+	mu          sync.Mutex
+	conn        net.Conn
+	compression Compression
+	chunkSize   int
+	buf         bytes.Buffer
+	chunk       []byte
+}
+
+// NewUDPWriter connects to address, chunkSize includes chunk header
+// and is kept within MinChunkSize and MaxChunkSize
+func NewUDPWriter(network, address string, c Compression, chunkSize int) (*UDPWriter, error) {
+	if network == "" {
+		network = "udp"
+	}
+	switch {
+	case chunkSize <= 0:
+		chunkSize = DefaultChunkSize
+	case chunkSize < MinChunkSize:
+		chunkSize = MinChunkSize
+	case chunkSize > MaxChunkSize:
+		chunkSize = MaxChunkSize
+	}
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("gelf: %w", err)
+	}
+	return &UDPWriter{
+		conn:        conn,
+		compression: c,
+		chunkSize:   chunkSize,
+		chunk:       make([]byte, chunkSize),
+	}, nil
+}
+
+func (w *UDPWriter) compress(p []byte) ([]byte, error) {
+	w.buf.Reset()
+	var zw io.WriteCloser
+	switch w.compression {
+	case GzipCompression:
+		zw = gzip.NewWriter(&w.buf)
+	case ZlibCompression:
+		zw = zlib.NewWriter(&w.buf)
+	default:
+		return p, nil
+	}
+	if _, err := zw.Write(p); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// Write single message
+func (w *UDPWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg, err := w.compress(p)
+	if err != nil {
+		return 0, fmt.Errorf("gelf: compress: %w", err)
+	}
+	if len(msg) <= w.chunkSize {
+		if _, err := w.conn.Write(msg); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if err := w.writeChunks(msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *UDPWriter) writeChunks(msg []byte) error {
+	size := w.chunkSize - chunkHeaderSize
+	count := (len(msg) + size - 1) / size
+	if count > MaxChunks {
+		return fmt.Errorf("gelf: message too large (%d bytes, %d chunks)", len(msg), count)
+	}
+
+	hdr := w.chunk[:chunkHeaderSize]
+	copy(hdr, chunkMagic)
+	if _, err := rand.Read(hdr[2:10]); err != nil {
+		return err
+	}
+	hdr[11] = byte(count)
+	for i := 0; i < count; i++ {
+		hdr[10] = byte(i)
+		part := msg[i*size:]
+		if len(part) > size {
+			part = part[:size]
+		}
+		n := copy(w.chunk[chunkHeaderSize:], part)
+		if _, err := w.conn.Write(w.chunk[:chunkHeaderSize+n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close the connection
+func (w *UDPWriter) Close() error {
+	return w.conn.Close()
+}
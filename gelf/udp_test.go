@@ -0,0 +1,156 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP return local UDP socket receiving datagrams
+func listenUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	return pc
+}
+
+// read datagram within timeout
+func readDatagram(t *testing.T, pc net.PacketConn) []byte {
+	t.Helper()
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, MaxChunkSize)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf[:n]
+}
+
+func TestUDPChunks(t *testing.T) {
+	pc := listenUDP(t)
+	w, err := NewUDPWriter("udp", pc.LocalAddr().String(), NoCompression, MinChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// small message is sent as single datagram
+	if _, err := w.Write([]byte(`{"short_message":"hi"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(readDatagram(t, pc)); got != `{"short_message":"hi"}` {
+		t.Errorf("datagram %q", got)
+	}
+
+	msg := []byte(strings.Repeat("0123456789", 120))
+	if _, err := w.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	size := MinChunkSize - chunkHeaderSize
+	count := (len(msg) + size - 1) / size
+	var id []byte
+	var got []byte
+	for i := 0; i < count; i++ {
+		chunk := readDatagram(t, pc)
+		if len(chunk) > MinChunkSize {
+			t.Errorf("chunk %d has %d bytes, more than chunk size", i, len(chunk))
+		}
+		if !bytes.Equal(chunk[:2], chunkMagic) {
+			t.Fatalf("chunk %d magic % x", i, chunk[:2])
+		}
+		if id == nil {
+			id = chunk[2:10]
+		} else if !bytes.Equal(chunk[2:10], id) {
+			t.Errorf("chunk %d message id % x, want % x", i, chunk[2:10], id)
+		}
+		if int(chunk[10]) != i || int(chunk[11]) != count {
+			t.Errorf("chunk sequence %d/%d, want %d/%d", chunk[10], chunk[11], i, count)
+		}
+		got = append(got, chunk[chunkHeaderSize:]...)
+	}
+	if !bytes.Equal(got, msg) {
+		t.Error("reassembled chunks differ from message")
+	}
+}
+
+func TestUDPTooManyChunks(t *testing.T) {
+	pc := listenUDP(t)
+	w, err := NewUDPWriter("udp", pc.LocalAddr().String(), NoCompression, MinChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	msg := make([]byte, MaxChunks*(MinChunkSize-chunkHeaderSize)+1)
+	if _, err := w.Write(msg); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("error %v, want message too large", err)
+	}
+}
+
+func TestUDPCompression(t *testing.T) {
+	tests := []struct {
+		name   string
+		c      Compression
+		reader func(r io.Reader) (io.Reader, error)
+	}{
+		{"none", NoCompression, func(r io.Reader) (io.Reader, error) { return r, nil }},
+		{"gzip", GzipCompression, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"zlib", ZlibCompression, func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }},
+	}
+	msg := `{"version":"1.1","short_message":"` + strings.Repeat("compressed ", 300) + `"}`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := listenUDP(t)
+			w, err := NewUDPWriter("udp", pc.LocalAddr().String(), tt.c, 8192)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			if _, err := w.Write([]byte(msg)); err != nil {
+				t.Fatal(err)
+			}
+			r, err := tt.reader(bytes.NewReader(readDatagram(t, pc)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != msg {
+				t.Errorf("round-trip %q", got)
+			}
+		})
+	}
+}
+
+func TestUDPChunkSize(t *testing.T) {
+	tests := []struct {
+		size int
+		want int
+	}{
+		{0, DefaultChunkSize},
+		{100, MinChunkSize},
+		{8192, 8192},
+		{100000, MaxChunkSize},
+	}
+	pc := listenUDP(t)
+	for _, tt := range tests {
+		w, err := NewUDPWriter("udp", pc.LocalAddr().String(), NoCompression, tt.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.chunkSize != tt.want {
+			t.Errorf("chunk size %d = %d, want %d", tt.size, w.chunkSize, tt.want)
+		}
+		w.Close()
+	}
+}
@@ -4,5 +4,7 @@ go 1.16
 
 require (
 	github.com/fatih/color v1.12.0
+	github.com/mattn/go-isatty v0.0.12
 	github.com/sirupsen/logrus v1.8.1
+	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae
 )
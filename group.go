@@ -0,0 +1,106 @@
+package slog
+
+import (
+	"os"
+)
+
+// Group creates field whose value is object of given key-values.
+// It can be passed in place of key-value pair, e.g.
+// Infow("done", Group("http", "method", "GET", "status", 200)).
+func Group(name string, keyVals ...interface{}) Field {
+	return Field{Key: name, Value: Object(ToFields(keyVals))}
+}
+
+// expandFields replaces Field in key position with its key and value,
+// the same slice is returned if there is no Field
+func expandFields(keyVals []interface{}) []interface{} {
+	var out []interface{}
+	for i := 0; i < len(keyVals); {
+		f, ok := keyVals[i].(Field)
+		if !ok {
+			end := i + 2
+			if end > len(keyVals) {
+				end = len(keyVals)
+			}
+			if out != nil {
+				out = append(out, keyVals[i:end]...)
+			}
+			i = end
+			continue
+		}
+		if out == nil {
+			out = make([]interface{}, 0, len(keyVals)+1)
+			out = append(out, keyVals[:i]...)
+		}
+		out = append(out, f.Key, f.Value)
+		i++
+	}
+	if out == nil {
+		return keyVals
+	}
+	return out
+}
+
+// nest fields under groups, the last group is the innermost
+func nestFields(groups []string, fields []Field) []Field {
+	if len(groups) == 0 || len(fields) == 0 {
+		return fields
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		fields = []Field{{Key: groups[i], Value: Object(fields)}}
+	}
+	return fields
+}
+
+// groupLogger nests fields of wrapped logger under group
+type groupLogger struct {
+	LoggerBase
+	next  Logger
+	group string
+}
+
+// WithGroup return logger which nests fields of subsequent *w calls under name.
+// For *HandlerLogger it is equal to HandlerLogger.WithGroup, other loggers are wrapped.
+func WithGroup(l Logger, name string) Logger {
+	if hl, ok := l.(*HandlerLogger); ok {
+		return hl.WithGroup(name)
+	}
+	gl := &groupLogger{next: l, group: name}
+	gl.LoggerBase = NewLoggerBase(gl)
+	return gl
+}
+
+// Unwrap return wrapped logger
+func (gl *groupLogger) Unwrap() Logger {
+	return gl.next
+}
+
+func (gl *groupLogger) HasLevel(lv Level) bool {
+	return gl.next.HasLevel(lv)
+}
+func (gl *groupLogger) SetLevel(lv Level) {
+	gl.next.SetLevel(lv)
+}
+
+// Exit using exit function of wrapped logger if available
+func (gl *groupLogger) Exit(code int) {
+	if e, ok := gl.next.(interface{ Exit(code int) }); ok {
+		e.Exit(code)
+		return
+	}
+	os.Exit(code)
+}
+
+func (gl *groupLogger) Log(lv Level, args ...interface{}) {
+	Leveled(gl.next).Log(lv, args...)
+}
+func (gl *groupLogger) Logf(lv Level, format string, args ...interface{}) {
+	Leveled(gl.next).Logf(lv, format, args...)
+}
+func (gl *groupLogger) Logw(lv Level, msg string, keyVals ...interface{}) {
+	if len(keyVals) == 0 {
+		Leveled(gl.next).Logw(lv, msg)
+		return
+	}
+	Leveled(gl.next).Logw(lv, msg, Group(gl.group, keyVals...))
+}
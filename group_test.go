@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGroupEncoding(t *testing.T) {
+	logs := []struct {
+		name string
+		log  func(lg Logger)
+	}{
+		{"group field", func(lg Logger) {
+			lg.Infow("m", Group("http", "method", "GET", "status", 200), "n", 1)
+		}},
+		{"with group", func(lg Logger) {
+			WithGroup(lg, "http").Infow("m", "method", "GET", "status", 200)
+		}},
+		{"nested with group", func(lg Logger) {
+			WithGroup(WithGroup(lg, "http"), "req").Infow("m", "method", "GET")
+		}},
+		{"group field inside group", func(lg Logger) {
+			WithGroup(lg, "http").Infow("m", "method", "GET", Group("resp", "status", 200))
+		}},
+		{"dotted key inside group", func(lg Logger) {
+			WithGroup(lg, "http").Infow("m", "req.method", "GET")
+		}},
+		{"without fields", func(lg Logger) {
+			WithGroup(lg, "http").Infow("m")
+		}},
+	}
+	tests := []struct {
+		formatter string
+		want      []string
+	}{
+		{"json", []string{
+			`{"level":"info","msg":"m","http":{"method":"GET","status":200},"n":1}`,
+			`{"level":"info","msg":"m","http":{"method":"GET","status":200}}`,
+			`{"level":"info","msg":"m","http":{"req":{"method":"GET"}}}`,
+			`{"level":"info","msg":"m","http":{"method":"GET","resp":{"status":200}}}`,
+			`{"level":"info","msg":"m","http":{"req.method":"GET"}}`,
+			`{"level":"info","msg":"m"}`,
+		}},
+		{"logfmt", []string{
+			`level=info msg=m http.method=GET http.status=200 n=1`,
+			`level=info msg=m http.method=GET http.status=200`,
+			`level=info msg=m http.req.method=GET`,
+			`level=info msg=m http.method=GET http.resp.status=200`,
+			`level=info msg=m http.req.method=GET`,
+			`level=info msg=m`,
+		}},
+		{"text", []string{
+			"INFOO m\thttp.method=\"GET\" http.status=200 n=1",
+			"INFOO m\thttp.method=\"GET\" http.status=200",
+			"INFOO m\thttp.req.method=\"GET\"",
+			"INFOO m\thttp.method=\"GET\" http.resp.status=200",
+			"INFOO m\thttp.req.method=\"GET\"",
+			"INFOO m",
+		}},
+	}
+	for _, tt := range tests {
+		for i, l := range logs {
+			t.Run(tt.formatter+"/"+l.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				lg, err := NewStdLogger(&buf, InfoLevel, Options{"formatter": tt.formatter, "timestampFormat": "none", "color": "never"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				l.log(lg)
+				if got := buf.String(); got != tt.want[i]+"\n" {
+					t.Errorf("got  %q\nwant %q", got, tt.want[i]+"\n")
+				}
+			})
+		}
+	}
+}
+
+func TestWithGroupWrapped(t *testing.T) {
+	// logger which is not *HandlerLogger is wrapped, nesting is the same
+	var buf bytes.Buffer
+	hl, err := NewStdLogger(&buf, InfoLevel, Options{"formatter": "json", "timestampFormat": "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := WithGroup(WithGroup(NewHookedLogger(hl), "http"), "req")
+	lg.Infow("m", "method", "GET", Group("resp", "status", 200))
+	want := `{"level":"info","msg":"m","http":{"req":{"method":"GET","resp":{"status":200}}}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,356 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Encoder formats entry into buffer
+type Encoder interface {
+	Encode(buf *bytes.Buffer, e *Entry) error
+}
+
+// ContentTyper is implemented by encoder which declares MIME type of the encoded entry
+type ContentTyper interface {
+	ContentType() string
+}
+
+// ContentTypeJSON is content type of encoder writing each entry as JSON object
+const ContentTypeJSON = "application/json"
+
+// IsJSONEncoder return true if enc writes each entry as JSON object (see ContentTyper)
+func IsJSONEncoder(enc Encoder) bool {
+	ct, ok := enc.(ContentTyper)
+	return ok && ct.ContentType() == ContentTypeJSON
+}
+
+// Handler writes or forwards entry
+type Handler interface {
+	Handle(e *Entry) error
+}
+
+// HandlerFunc adapts function to Handler
+type HandlerFunc func(e *Entry) error
+
+// Handle entry by calling the function
+func (fn HandlerFunc) Handle(e *Entry) error {
+	return fn(e)
+}
+
+// Flusher is implemented by handler or writer which buffers entries
+type Flusher interface {
+	Flush() error
+}
+
+// EntryLogger is implemented by logger which accepts entry directly
+type EntryLogger interface {
+	LogEntry(e *Entry)
+}
+
+// WriterHandler encodes entry and writes it to the writer.
+// It is safe for concurrent use.
+type WriterHandler struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc Encoder
+	buf bytes.Buffer
+}
+
+// NewWriterHandler creates handler writing entries encoded with enc to w
+func NewWriterHandler(w io.Writer, enc Encoder) *WriterHandler {
+	return &WriterHandler{w: w, enc: enc}
+}
+
+// Handle encodes and writes the entry
+func (h *WriterHandler) Handle(e *Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf.Reset()
+	if err := h.enc.Encode(&h.buf, e); err != nil {
+		return err
+	}
+	_, err := h.w.Write(h.buf.Bytes())
+	return err
+}
+
+// Flush writer if it implements Flusher
+func (h *WriterHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if f, ok := h.w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close writer if it implements io.Closer
+func (h *WriterHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type multiHandler []Handler
+
+// MultiHandler passes entry to each handler, errors of all handlers are combined
+func MultiHandler(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+// Flush each handler which implements Flusher
+func (m multiHandler) Flush() error {
+	return m.each(func(h Handler) error {
+		if f, ok := h.(Flusher); ok {
+			return f.Flush()
+		}
+		return nil
+	})
+}
+
+// Close each handler which implements io.Closer
+func (m multiHandler) Close() error {
+	return m.each(func(h Handler) error {
+		if c, ok := h.(io.Closer); ok {
+			return c.Close()
+		}
+		return nil
+	})
+}
+
+func (m multiHandler) Handle(e *Entry) error {
+	return m.each(func(h Handler) error {
+		return h.Handle(e)
+	})
+}
+
+func (m multiHandler) each(fn func(h Handler) error) error {
+	var msgs []string
+	for _, h := range m {
+		if err := fn(h); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) != 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// HandlerLogger creates entry for each log and passes it to the handler.
+// Handler errors are passed to the package error handler.
+// Setters are safe for concurrent use with logging, each entry uses either
+// the old or the new setting.
+type HandlerLogger struct {
+	LevelLoggerBase
+	LoggerBase
+	h Handler
+
+	// mu serializes setters, cfg holds handlerConfig
+	mu  sync.Mutex
+	cfg atomic.Value
+}
+
+// handlerConfig of HandlerLogger, replaced as a whole by setters
+type handlerConfig struct {
+	name         string
+	reportCaller bool
+	ctx          context.Context
+	groups       []string
+	policy       FieldPolicy
+	redactor     Redactor
+	limits       Limits
+	clock        Clock
+	clockStart   time.Time
+}
+
+// NewHandlerLogger creates logger writing to given handler
+func NewHandlerLogger(h Handler, lv Level) *HandlerLogger {
+	hl := &HandlerLogger{h: h}
+	hl.LoggerBase = NewLoggerBase(hl)
+	hl.cfg.Store(handlerConfig{})
+	hl.SetLevel(lv)
+	return hl
+}
+
+func (hl *HandlerLogger) config() handlerConfig {
+	return hl.cfg.Load().(handlerConfig)
+}
+
+// update stores copy of the config modified by fn
+func (hl *HandlerLogger) update(fn func(c *handlerConfig)) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	c := hl.config()
+	fn(&c)
+	hl.cfg.Store(c)
+}
+
+// Handler return handler of the logger
+func (hl *HandlerLogger) Handler() Handler {
+	return hl.h
+}
+
+// Flush pending entries if handler supports it
+func (hl *HandlerLogger) Flush() error {
+	if f, ok := hl.h.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close handler if it implements io.Closer
+func (hl *HandlerLogger) Close() error {
+	if c, ok := hl.h.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// osExit terminates the program, replaced in tests
+var osExit = os.Exit
+
+// Exit flushes and closes the handler, then terminates the program with given code.
+// Fatal methods call it after the entry is written, so queued entries are not lost.
+func (hl *HandlerLogger) Exit(code int) {
+	if err := hl.Flush(); err != nil {
+		ReportError(fmt.Errorf("handler %T: flush: %w", hl.h, err))
+	}
+	if err := hl.Close(); err != nil {
+		ReportError(fmt.Errorf("handler %T: close: %w", hl.h, err))
+	}
+	osExit(code)
+}
+
+// Name of the logger, written in entry
+func (hl *HandlerLogger) Name() string {
+	return hl.config().name
+}
+
+// SetName of the logger
+func (hl *HandlerLogger) SetName(name string) {
+	hl.update(func(c *handlerConfig) { c.name = name })
+}
+
+// SetFieldPolicy set duplicate key handling and ordering of fields
+func (hl *HandlerLogger) SetFieldPolicy(p FieldPolicy) {
+	hl.update(func(c *handlerConfig) { c.policy = p })
+}
+
+// SetLimits set size limits of message and fields
+func (hl *HandlerLogger) SetLimits(lm Limits) {
+	hl.update(func(c *handlerConfig) { c.limits = lm })
+}
+
+// SetRedactor set redactor applied to each entry before limits and handler, nil disables redaction
+func (hl *HandlerLogger) SetRedactor(r Redactor) {
+	hl.update(func(c *handlerConfig) { c.redactor = r })
+}
+
+// SetClock set clock of entry timestamp, nil uses package clock (see SetClock).
+// Elapsed timestamp is measured from current time of the clock (see Entry.Start).
+func (hl *HandlerLogger) SetClock(now Clock) {
+	var start time.Time
+	if now != nil {
+		start = now()
+	}
+	hl.update(func(c *handlerConfig) {
+		c.clock = now
+		c.clockStart = start
+	})
+}
+
+// SetReportCaller enable or disable caller information in entry
+func (hl *HandlerLogger) SetReportCaller(enable bool) {
+	hl.update(func(c *handlerConfig) { c.reportCaller = enable })
+}
+
+// WithName return new logger with given name sharing the same handler
+func (hl *HandlerLogger) WithName(name string) *HandlerLogger {
+	return hl.clone(func(c *handlerConfig) { c.name = name })
+}
+
+// WithContext return new logger which attaches ctx to each entry
+// (e.g. carrying span), sharing the same handler
+func (hl *HandlerLogger) WithContext(ctx context.Context) *HandlerLogger {
+	return hl.clone(func(c *handlerConfig) { c.ctx = ctx })
+}
+
+// WithGroup return new logger which nests fields under name,
+// sharing the same handler
+func (hl *HandlerLogger) WithGroup(name string) *HandlerLogger {
+	return hl.clone(func(c *handlerConfig) {
+		c.groups = append(c.groups[:len(c.groups):len(c.groups)], name)
+	})
+}
+
+// clone return logger sharing handler with config modified by fn
+func (hl *HandlerLogger) clone(fn func(c *handlerConfig)) *HandlerLogger {
+	nl := NewHandlerLogger(hl.h, 0)
+	c := hl.config()
+	fn(&c)
+	nl.cfg.Store(c)
+	nl.LevelLoggerBase.SetLevelMode(hl.LevelMode())
+	nl.LevelLoggerBase.SetLevel(hl.Level())
+	return nl
+}
+
+// LogEntry writes entry created elsewhere (e.g. by hook), the level is not checked
+func (hl *HandlerLogger) LogEntry(e *Entry) {
+	c := hl.config()
+	if e.Logger == "" {
+		e.Logger = c.name
+	}
+	if e.Context == nil {
+		e.Context = c.ctx
+	}
+	if e.Start.IsZero() {
+		e.Start = c.clockStart
+	}
+	e.Fields = c.policy.Apply(nestFields(c.groups, e.Fields))
+	if c.redactor != nil {
+		c.redactor.Redact(e)
+	}
+	c.limits.Apply(e)
+	if e.Caller == nil && c.reportCaller {
+		e.Caller = CallerOf()
+	}
+	if err := hl.h.Handle(e); err != nil {
+		ReportError(fmt.Errorf("handler %T: %w", hl.h, err))
+	}
+}
+
+// now return time of the logger clock
+func (hl *HandlerLogger) now() time.Time {
+	if c := hl.config().clock; c != nil {
+		return c()
+	}
+	return Now()
+}
+
+func (hl *HandlerLogger) Log(lv Level, args ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.LogEntry(newEntry(hl.now(), lv, fmt.Sprint(args...), nil))
+	}
+}
+func (hl *HandlerLogger) Logf(lv Level, format string, args ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.LogEntry(newEntry(hl.now(), lv, fmt.Sprintf(format, args...), nil))
+	}
+}
+func (hl *HandlerLogger) Logw(lv Level, msg string, keyVals ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.LogEntry(newEntry(hl.now(), lv, msg, keyVals))
+	}
+}
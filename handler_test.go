@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// entryRecorder keeps copy of the last handled entry
+type entryRecorder struct {
+	mu   sync.Mutex
+	last Entry
+}
+
+func (r *entryRecorder) Handle(e *Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = *e
+	return nil
+}
+
+func (r *entryRecorder) entry() Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestHandlerLoggerSetters(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name  string
+		set   func(hl *HandlerLogger)
+		check func(e Entry) bool
+	}{
+		{"name", func(hl *HandlerLogger) { hl.SetName("api") },
+			func(e Entry) bool { return e.Logger == "api" }},
+		{"report caller", func(hl *HandlerLogger) { hl.SetReportCaller(true) },
+			func(e Entry) bool { return e.Caller != nil }},
+		{"limits", func(hl *HandlerLogger) { hl.SetLimits(Limits{MaxMessage: 3}) },
+			func(e Entry) bool { return e.Message == TruncateString("message", 3) }},
+		{"field policy", func(hl *HandlerLogger) { hl.SetFieldPolicy(FieldPolicy{Sort: true}) },
+			func(e Entry) bool { return len(e.Fields) == 2 && e.Fields[0].Key == "a" }},
+		{"clock", func(hl *HandlerLogger) { hl.SetClock(func() time.Time { return fixed }) },
+			func(e Entry) bool { return e.Time.Equal(fixed) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &entryRecorder{}
+			hl := NewHandlerLogger(rec, InfoLevel)
+			tt.set(hl)
+			hl.Infow("message", "b", 1, "a", 2)
+			if e := rec.entry(); !tt.check(e) {
+				t.Errorf("setting not applied: %+v", e)
+			}
+			// derived logger keeps the setting
+			hl.WithContext(context.Background()).Infow("message", "b", 1, "a", 2)
+			if e := rec.entry(); !tt.check(e) {
+				t.Errorf("setting not applied to derived logger: %+v", e)
+			}
+		})
+	}
+}
+
+func TestHandlerLoggerConcurrentSetters(t *testing.T) {
+	rec := &entryRecorder{}
+	hl := NewHandlerLogger(rec, InfoLevel)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				hl.SetName("api")
+				hl.SetReportCaller(j%2 == 0)
+				hl.SetLimits(Limits{MaxValue: j})
+				hl.SetFieldPolicy(FieldPolicy{Sort: j%2 == 0})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				hl.Infow("message", "key", "value")
+				_ = hl.WithName("child").Name()
+			}
+		}()
+	}
+	wg.Wait()
+	if hl.Name() != "api" {
+		t.Errorf("Name() = %q, want api", hl.Name())
+	}
+}
+
+// closingHandler records handled messages, flush and close calls
+type closingHandler struct {
+	calls []string
+}
+
+func (h *closingHandler) Handle(e *Entry) error {
+	h.calls = append(h.calls, "handle "+e.Message)
+	return nil
+}
+
+func (h *closingHandler) Flush() error {
+	h.calls = append(h.calls, "flush")
+	return nil
+}
+
+func (h *closingHandler) Close() error {
+	h.calls = append(h.calls, "close")
+	return nil
+}
+
+func TestHandlerLoggerExit(t *testing.T) {
+	defer func(fn func(int)) { osExit = fn }(osExit)
+	tests := []struct {
+		name     string
+		call     func(l Logger)
+		wantCode int
+	}{
+		{"fatal", func(l Logger) { l.Fatal("bye") }, 1},
+		{"fatalw", func(l Logger) { l.Fatalw("bye", "k", 1) }, 1},
+		{"hooked logger", func(l Logger) { NewHookedLogger(l).Fatalf("%s", "bye") }, 1},
+		{"group logger", func(l Logger) { WithGroup(l, "req").Fatalln("bye") }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := -1
+			osExit = func(c int) { code = c }
+			h := &closingHandler{}
+			tt.call(NewHandlerLogger(h, InfoLevel))
+			if code != tt.wantCode {
+				t.Errorf("exit code %d, want %d", code, tt.wantCode)
+			}
+			if want := []string{"handle bye", "flush", "close"}; !reflect.DeepEqual(h.calls, want) {
+				t.Errorf("calls %q, want %q", h.calls, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Hook observes or mutates entries before they are written.
+// Fire is called for entries with level listed by Levels, empty Levels means all levels
+// (including levels registered after the hook is added).
+type Hook interface {
+	Levels() []Level
+	Fire(e *Entry) error
+}
+
+type funcHook struct {
+	levels []Level
+	fn     func(e *Entry) error
+}
+
+// HookFunc creates hook from function, fired for given levels or all levels if empty
+func HookFunc(fn func(e *Entry) error, levels ...Level) Hook {
+	return &funcHook{levels: levels, fn: fn}
+}
+
+func (h *funcHook) Levels() []Level {
+	return h.levels
+}
+
+func (h *funcHook) Fire(e *Entry) error {
+	return h.fn(e)
+}
+
+// HookedLogger fires hooks for each entry before passing it to the wrapped logger.
+// Hook errors are passed to the error handler of the logger,
+// or to the package error handler if not set.
+type HookedLogger struct {
+	LoggerBase
+	next Logger
+
+	mu      sync.RWMutex
+	hooks   []levelHook
+	onError ErrorHandler
+}
+
+// levelHook is hook with its levels, nil levels means all levels
+type levelHook struct {
+	Hook
+	levels map[Level]bool
+}
+
+// NewHookedLogger wraps logger with given hooks
+func NewHookedLogger(l Logger, hooks ...Hook) *HookedLogger {
+	hl := &HookedLogger{next: l}
+	hl.LoggerBase = NewLoggerBase(hl)
+	for _, h := range hooks {
+		hl.AddHook(h)
+	}
+	return hl
+}
+
+// AddHook to the logger
+func (hl *HookedLogger) AddHook(h Hook) {
+	lh := levelHook{Hook: h}
+	if levels := h.Levels(); len(levels) != 0 {
+		lh.levels = make(map[Level]bool, len(levels))
+		for _, lv := range levels {
+			lh.levels[lv] = true
+		}
+	}
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	hl.hooks = append(hl.hooks[:len(hl.hooks):len(hl.hooks)], lh)
+}
+
+// SetErrorHandler set handler for hook errors, nil uses package error handler
+func (hl *HookedLogger) SetErrorHandler(fn ErrorHandler) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	hl.onError = fn
+}
+
+// Unwrap return wrapped logger
+func (hl *HookedLogger) Unwrap() Logger {
+	return hl.next
+}
+
+func (hl *HookedLogger) HasLevel(lv Level) bool {
+	return hl.next.HasLevel(lv)
+}
+func (hl *HookedLogger) SetLevel(lv Level) {
+	hl.next.SetLevel(lv)
+}
+
+// Exit using exit function of wrapped logger if available
+func (hl *HookedLogger) Exit(code int) {
+	if e, ok := hl.next.(interface{ Exit(code int) }); ok {
+		e.Exit(code)
+		return
+	}
+	os.Exit(code)
+}
+
+func (hl *HookedLogger) fire(e *Entry) {
+	hl.mu.RLock()
+	hooks := hl.hooks
+	onError := hl.onError
+	hl.mu.RUnlock()
+
+	for _, h := range hooks {
+		if h.levels != nil && !h.levels[e.Level] {
+			continue
+		}
+		if err := h.Fire(e); err != nil {
+			err = fmt.Errorf("hook %T: %w", h.Hook, err)
+			if onError != nil {
+				onError(err)
+			} else {
+				ReportError(err)
+			}
+		}
+	}
+}
+
+func (hl *HookedLogger) write(e *Entry) {
+	hl.fire(e)
+	if el, ok := hl.next.(EntryLogger); ok {
+		if hl.next.HasLevel(e.Level) {
+			el.LogEntry(e)
+		}
+	} else if len(e.Fields) == 0 {
+		Leveled(hl.next).Log(e.Level, e.Message)
+	} else {
+		Leveled(hl.next).Logw(e.Level, e.Message, e.KeyVals()...)
+	}
+}
+
+// now return time of the clock of the wrapped HandlerLogger (see HandlerLogger.SetClock),
+// or package clock
+func (hl *HookedLogger) now() time.Time {
+	l := hl.next
+	for {
+		switch v := l.(type) {
+		case *HandlerLogger:
+			return v.now()
+		case interface{ Unwrap() Logger }:
+			l = v.Unwrap()
+		default:
+			return Now()
+		}
+	}
+}
+
+func (hl *HookedLogger) Log(lv Level, args ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.write(newEntry(hl.now(), lv, fmt.Sprint(args...), nil))
+	}
+}
+func (hl *HookedLogger) Logf(lv Level, format string, args ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.write(newEntry(hl.now(), lv, fmt.Sprintf(format, args...), nil))
+	}
+}
+func (hl *HookedLogger) Logw(lv Level, msg string, keyVals ...interface{}) {
+	if hl.HasLevel(lv) {
+		hl.write(newEntry(hl.now(), lv, msg, keyVals))
+	}
+}
@@ -0,0 +1,113 @@
+package slog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHookedLoggerLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []Level
+		lv     Level
+		want   bool
+	}{
+		{"all levels", nil, InfoLevel, true},
+		{"listed level", []Level{WarnLevel, ErrorLevel}, ErrorLevel, true},
+		{"other level", []Level{WarnLevel}, InfoLevel, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fired := false
+			hl := NewHookedLogger(NewHandlerLogger(&entryRecorder{}, TraceLevel),
+				HookFunc(func(e *Entry) error { fired = true; return nil }, tt.levels...))
+			hl.Logw(tt.lv, "msg")
+			if fired != tt.want {
+				t.Errorf("fired = %v, want %v", fired, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookedLoggerLevelRegisteredLater(t *testing.T) {
+	var fired []Level
+	hl := NewHookedLogger(NewHandlerLogger(&entryRecorder{}, InfoLevel),
+		HookFunc(func(e *Entry) error { fired = append(fired, e.Level); return nil }))
+	notice := MustRegisterLevel(LevelSpec{Name: "hook-notice", Fixed: "NOTIC", Severity: InfoSeverity + 40})
+	hl.Logw(notice, "msg")
+	if !reflect.DeepEqual(fired, []Level{notice}) {
+		t.Errorf("fired for %v, want level registered after hook", fired)
+	}
+}
+
+func TestHookedLoggerModifiesEntry(t *testing.T) {
+	hook := HookFunc(func(e *Entry) error {
+		e.Message = "[audit] " + e.Message
+		e.SetField("host", "web-1")
+		return nil
+	})
+	tests := []struct {
+		name   string
+		next   func(rec *entryRecorder) Logger
+		wantKV []interface{}
+	}{
+		{"entry logger", func(rec *entryRecorder) Logger { return NewHandlerLogger(rec, InfoLevel) },
+			[]interface{}{"user", "bob", "host", "web-1"}},
+		{"wrapped entry logger", func(rec *entryRecorder) Logger { return WithGroup(NewHandlerLogger(rec, InfoLevel), "req") },
+			[]interface{}{"req", Object{{"user", "bob"}, {"host", "web-1"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &entryRecorder{}
+			NewHookedLogger(tt.next(rec), hook).Infow("login", "user", "bob")
+			e := rec.entry()
+			if e.Message != "[audit] login" {
+				t.Errorf("message %q", e.Message)
+			}
+			if got := e.KeyVals(); !reflect.DeepEqual(got, tt.wantKV) {
+				t.Errorf("fields %#v, want %#v", got, tt.wantKV)
+			}
+		})
+	}
+}
+
+func TestHookedLoggerErrors(t *testing.T) {
+	var pkgErrs []error
+	SetErrorHandler(func(err error) { pkgErrs = append(pkgErrs, err) })
+	defer SetErrorHandler(nil)
+
+	errFailed := errors.New("alert failed")
+	failing := HookFunc(func(e *Entry) error { return errFailed })
+	rec := &entryRecorder{}
+	hl := NewHookedLogger(NewHandlerLogger(rec, InfoLevel), failing)
+
+	hl.Info("to package handler")
+	if len(pkgErrs) != 1 || !errors.Is(pkgErrs[0], errFailed) {
+		t.Errorf("package handler received %v", pkgErrs)
+	}
+	if rec.entry().Message != "to package handler" {
+		t.Error("entry not written after hook error")
+	}
+
+	var ownErrs []error
+	hl.SetErrorHandler(func(err error) { ownErrs = append(ownErrs, err) })
+	hl.Info("to logger handler")
+	if len(ownErrs) != 1 || !errors.Is(ownErrs[0], errFailed) || len(pkgErrs) != 1 {
+		t.Errorf("logger handler received %v, package handler %v", ownErrs, pkgErrs)
+	}
+}
+
+func TestHookedLoggerClock(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	next := NewHandlerLogger(&entryRecorder{}, InfoLevel)
+	next.SetClock(func() time.Time { return fixed })
+
+	var got time.Time
+	hook := HookFunc(func(e *Entry) error { got = e.Time; return nil })
+	NewHookedLogger(WithGroup(next, "req"), hook).Info("msg")
+	if !got.Equal(fixed) {
+		t.Errorf("entry time %v, want clock of wrapped logger %v", got, fixed)
+	}
+}
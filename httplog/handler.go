@@ -0,0 +1,335 @@
+package httplog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Default configuration values
+const (
+	DefaultBatchSize     = 100
+	DefaultBatchBytes    = 1 << 20
+	DefaultFlushInterval = time.Second
+	DefaultQueueSize     = 10000
+	DefaultMaxRetries    = 3
+	DefaultMinBackoff    = 500 * time.Millisecond
+	DefaultMaxBackoff    = 30 * time.Second
+	DefaultTimeout       = 10 * time.Second
+)
+
+// ErrClosed is returned when handling entry after the handler is closed
+var ErrClosed = errors.New("httplog: handler closed")
+
+// Config of HTTP handler, zero value uses defaults
+type Config struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	Encoder PayloadEncoder
+	Client  *http.Client
+	Gzip    bool
+
+	// batch is sent when number of entries or approximate size is reached,
+	// or after flush interval
+	BatchSize     int
+	BatchBytes    int
+	FlushInterval time.Duration
+
+	// QueueSize is maximum entries waiting to be sent, oldest entries are dropped
+	QueueSize int
+
+	// retry on network error, 5xx and 429
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnError receives send errors, default to slog.ReportError.
+	// It is called without lock held, so it may log to the same sink.
+	OnError slog.ErrorHandler
+}
+
+// Handler accumulates entries and sends them in batch.
+// Entries are encoded when handled, so values may change after logging,
+// batches are sent in background goroutine.
+type Handler struct {
+	cfg Config
+
+	mu      sync.Mutex
+	pending []Record
+	size    int
+	dropped int
+	closed  bool
+
+	sendMu sync.Mutex
+	kick   chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewHandler creates handler and starts background sender
+func NewHandler(cfg Config) (*Handler, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("httplog: url is required")
+	}
+	if cfg.Encoder == nil {
+		cfg.Encoder = &WebhookEncoder{}
+	}
+	if we, ok := cfg.Encoder.(*WebhookEncoder); ok && we.Element != nil && !slog.IsJSONEncoder(we.Element) {
+		return nil, fmt.Errorf("httplog: webhook element encoder %T does not write JSON", we.Element)
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.BatchBytes <= 0 {
+		cfg.BatchBytes = DefaultBatchBytes
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = slog.ReportError
+	}
+
+	h := &Handler{
+		cfg:  cfg,
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	h.wg.Add(1)
+	go h.run()
+
+	return h, nil
+}
+
+// Handle encodes entry and adds it to the pending batch
+func (h *Handler) Handle(e *slog.Entry) error {
+	var buf bytes.Buffer
+	if err := h.cfg.Encoder.EncodeRecord(&buf, e); err != nil {
+		return fmt.Errorf("httplog: encode: %w", err)
+	}
+	rec := Record{Time: e.Time, Level: e.Level, Logger: e.Logger, Data: buf.Bytes()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrClosed
+	}
+
+	h.pending = append(h.pending, rec)
+	h.size += len(rec.Data)
+	for len(h.pending) > h.cfg.QueueSize {
+		h.size -= len(h.pending[0].Data)
+		h.pending[0] = Record{}
+		h.pending = h.pending[1:]
+		h.dropped++
+	}
+
+	if len(h.pending) >= h.cfg.BatchSize || h.size >= h.cfg.BatchBytes {
+		select {
+		case h.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (h *Handler) run() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+		case <-h.kick:
+		}
+		if err := h.Flush(); err != nil {
+			h.cfg.OnError(err)
+		}
+	}
+}
+
+// take at most one batch from pending records and number of entries dropped since
+// last call, which is reported by the caller after unlock
+func (h *Handler) takeBatch() ([]Record, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dropped := h.dropped
+	h.dropped = 0
+
+	n, size := 0, 0
+	for n < len(h.pending) && n < h.cfg.BatchSize {
+		// batch keeps at least one record
+		if n > 0 && size+len(h.pending[n].Data) > h.cfg.BatchBytes {
+			break
+		}
+		size += len(h.pending[n].Data)
+		n++
+	}
+	if n == 0 {
+		return nil, dropped
+	}
+	batch := make([]Record, n)
+	copy(batch, h.pending)
+	for i := 0; i < n; i++ {
+		h.pending[i] = Record{}
+	}
+	h.pending = h.pending[n:]
+	h.size -= size
+
+	return batch, dropped
+}
+
+// Flush sends all pending entries, return the last send error
+func (h *Handler) Flush() error {
+	h.sendMu.Lock()
+	defer h.sendMu.Unlock()
+
+	var lastErr error
+	for {
+		batch, dropped := h.takeBatch()
+		if dropped > 0 {
+			h.cfg.OnError(fmt.Errorf("httplog: %d entries dropped", dropped))
+		}
+		if len(batch) == 0 {
+			return lastErr
+		}
+		if err := h.send(batch); err != nil {
+			lastErr = err
+		}
+	}
+}
+
+// Close flushes pending entries and stops background sender
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.done)
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	return h.Flush()
+}
+
+func (h *Handler) encode(batch []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := h.cfg.Encoder.EncodePayload(&buf, batch); err != nil {
+		return nil, err
+	}
+	if !h.cfg.Gzip {
+		return buf.Bytes(), nil
+	}
+
+	var zbuf bytes.Buffer
+	zw := gzip.NewWriter(&zbuf)
+	if _, err := zw.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return zbuf.Bytes(), nil
+}
+
+// send batch, retry with exponential backoff
+func (h *Handler) send(batch []Record) error {
+	body, err := h.encode(batch)
+	if err != nil {
+		return fmt.Errorf("httplog: encode: %w", err)
+	}
+
+	backoff := h.cfg.MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, wait, err := h.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= h.cfg.MaxRetries {
+			return fmt.Errorf("httplog: %d entries not sent: %w", len(batch), err)
+		}
+
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+			if backoff > h.cfg.MaxBackoff {
+				backoff = h.cfg.MaxBackoff
+			}
+		}
+		select {
+		case <-time.After(wait):
+		case <-h.done:
+			// closing, send remaining without waiting
+		}
+	}
+}
+
+// post body, return whether request should be retried and delay requested by server
+func (h *Handler) post(body []byte) (bool, time.Duration, error) {
+	req, err := http.NewRequest(h.cfg.Method, h.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header.Set("Content-Type", h.cfg.Encoder.ContentType())
+	if h.cfg.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range h.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.cfg.Client.Do(req)
+	if err != nil {
+		return true, 0, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	var wait time.Duration
+	if sec, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && sec >= 0 {
+		wait = time.Duration(sec) * time.Second
+		if wait > h.cfg.MaxBackoff {
+			wait = h.cfg.MaxBackoff
+		}
+	}
+	return retry, wait, err
+}
@@ -0,0 +1,321 @@
+package httplog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// collector is webhook endpoint recording messages of each request,
+// responses are taken from statuses, then 200
+type collector struct {
+	mu       sync.Mutex
+	statuses []int
+	requests [][]string
+	bodies   []map[string]interface{}
+	received chan struct{}
+}
+
+func newCollector(t *testing.T, statuses ...int) (*collector, *httptest.Server) {
+	c := &collector{statuses: statuses, received: make(chan struct{}, 100)}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	var elems []map[string]interface{}
+	if err := json.NewDecoder(body).Decode(&elems); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	status := http.StatusOK
+	if len(c.statuses) > 0 {
+		status, c.statuses = c.statuses[0], c.statuses[1:]
+	}
+	var msgs []string
+	for _, e := range elems {
+		msgs = append(msgs, fmt.Sprint(e[slog.KeyMsg]))
+	}
+	c.requests = append(c.requests, msgs)
+	c.bodies = append(c.bodies, elems...)
+	c.mu.Unlock()
+
+	w.WriteHeader(status)
+	c.received <- struct{}{}
+}
+
+func (c *collector) snapshot() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]string(nil), c.requests...)
+}
+
+func (c *collector) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request received")
+	}
+}
+
+// errRecorder collects errors passed to OnError
+type errRecorder struct {
+	mu   sync.Mutex
+	errs []string
+}
+
+func (r *errRecorder) handle(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err.Error())
+}
+
+func (r *errRecorder) all() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.errs...)
+}
+
+func messages(n int) []string {
+	msgs := make([]string, n)
+	for i := range msgs {
+		msgs[i] = fmt.Sprint("m", i)
+	}
+	return msgs
+}
+
+func flatten(reqs [][]string) []string {
+	var all []string
+	for _, r := range reqs {
+		all = append(all, r...)
+	}
+	return all
+}
+
+func TestHandlerBatching(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		count    int
+		maxBatch int
+	}{
+		{"batch size", Config{BatchSize: 3}, 7, 3},
+		{"batch bytes", Config{BatchBytes: 150}, 6, 2},
+		{"gzip", Config{BatchSize: 2, Gzip: true}, 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newCollector(t)
+			cfg := tt.cfg
+			cfg.URL = srv.URL
+			cfg.FlushInterval = time.Hour
+			h, err := NewHandler(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+			want := messages(tt.count)
+			for _, m := range want {
+				lg.Info(m)
+			}
+			// full batch is sent without waiting for flush interval
+			c.wait(t)
+			if err := h.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reqs := c.snapshot()
+			for _, r := range reqs {
+				if len(r) > tt.maxBatch {
+					t.Errorf("batch of %d entries, want at most %d", len(r), tt.maxBatch)
+				}
+			}
+			if got := flatten(reqs); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("sent %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestHandlerFlushOnClose(t *testing.T) {
+	c, srv := newCollector(t)
+	h, err := NewHandler(Config{URL: srv.URL, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+	lg.Info("first")
+	lg.Info("second")
+	if reqs := c.snapshot(); len(reqs) != 0 {
+		t.Fatalf("sent before close: %v", reqs)
+	}
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reqs := c.snapshot()
+	if len(reqs) != 1 || strings.Join(reqs[0], ",") != "first,second" {
+		t.Errorf("requests %v, want [[first second]]", reqs)
+	}
+	if err := h.Handle(slog.NewEntry(slog.InfoLevel, "late", nil)); err != ErrClosed {
+		t.Errorf("Handle after close = %v, want ErrClosed", err)
+	}
+}
+
+func TestHandlerDropOnOverflow(t *testing.T) {
+	c, srv := newCollector(t)
+	rec := &errRecorder{}
+	h, err := NewHandler(Config{URL: srv.URL, BatchSize: 4, QueueSize: 4, FlushInterval: time.Hour, OnError: rec.handle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	// block sender so the queue overflows
+	h.sendMu.Lock()
+	for _, m := range messages(7) {
+		lg.Info(m)
+	}
+	h.sendMu.Unlock()
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(flatten(c.snapshot()), ","); got != "m3,m4,m5,m6" {
+		t.Errorf("sent %s, want m3,m4,m5,m6", got)
+	}
+	if errs := rec.all(); len(errs) != 1 || !strings.Contains(errs[0], "3 entries dropped") {
+		t.Errorf("errors %q, want 3 entries dropped", errs)
+	}
+}
+
+func TestHandlerRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+		wantErr  string
+	}{
+		{"success", nil, 1, ""},
+		{"retry 5xx", []int{503, 500}, 3, ""},
+		{"retry 429", []int{429}, 2, ""},
+		{"retries exhausted", []int{503, 503, 503}, 3, "503"},
+		{"client error not retried", []int{400}, 1, "400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newCollector(t, tt.statuses...)
+			rec := &errRecorder{}
+			h, err := NewHandler(Config{
+				URL:           srv.URL,
+				FlushInterval: time.Hour,
+				MaxRetries:    2,
+				MinBackoff:    time.Millisecond,
+				MaxBackoff:    2 * time.Millisecond,
+				OnError:       rec.handle,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			slog.NewHandlerLogger(h, slog.InfoLevel).Info("msg")
+			err = h.Close()
+
+			if got := len(c.snapshot()); got != tt.requests {
+				t.Errorf("%d requests, want %d", got, tt.requests)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandlerEncodesAtLogTime(t *testing.T) {
+	c, srv := newCollector(t)
+	h, err := NewHandler(Config{URL: srv.URL, FlushInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	// values are modified while the sender runs
+	m := map[string]interface{}{"n": 0}
+	for i := 0; i < 50; i++ {
+		m["n"] = i
+		lg.Infow("msg", "m", m, "i", i)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.bodies) != 50 {
+		t.Fatalf("%d entries sent, want 50", len(c.bodies))
+	}
+	for _, b := range c.bodies {
+		if got := b["m"].(map[string]interface{})["n"]; got != b["i"] {
+			t.Errorf("map value %v logged with i=%v", got, b["i"])
+		}
+	}
+}
+
+func TestHandlerOnErrorLogsBack(t *testing.T) {
+	c, srv := newCollector(t)
+	var lg *slog.HandlerLogger
+	h, err := NewHandler(Config{URL: srv.URL, BatchSize: 4, QueueSize: 4, FlushInterval: time.Hour,
+		OnError: func(err error) { lg.Warn(err.Error()) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg = slog.NewHandlerLogger(h, slog.InfoLevel)
+
+	h.sendMu.Lock()
+	for _, m := range messages(5) {
+		lg.Info(m)
+	}
+	h.sendMu.Unlock()
+
+	// error handler logging into the same handler must not deadlock
+	done := make(chan error, 1)
+	go func() { done <- h.Flush() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("flush deadlocked while reporting dropped entries")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(flatten(c.snapshot()), ","); !strings.Contains(got, "httplog: 1 entries dropped") {
+		t.Errorf("sent %s, want dropped entries report", got)
+	}
+}
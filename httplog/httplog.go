@@ -0,0 +1,178 @@
+package httplog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ipsusila/slog"
+)
+
+// Name of HTTP logger
+const Name = "http"
+
+type httpConstructor struct{}
+
+const (
+	fieldURL           = "url"
+	fieldMethod        = "method"
+	fieldFormat        = "format"
+	fieldLabels        = "labels"
+	fieldIndex         = "index"
+	fieldHeaders       = "headers"
+	fieldBatchSize     = "batchSize"
+	fieldBatchBytes    = "batchBytes"
+	fieldFlushInterval = "flushInterval"
+	fieldQueueSize     = "queueSize"
+	fieldGzip          = "gzip"
+	fieldMaxRetries    = "maxRetries"
+	fieldMinBackoff    = "minBackoff"
+	fieldMaxBackoff    = "maxBackoff"
+	fieldTimeout       = "timeout"
+	fieldName          = "name"
+	fieldReportCaller  = "reportCaller"
+	fieldOnError       = "onError"
+	defaultFormat      = "webhook"
+)
+
+// options supported by HTTP logger
+var httpSchema = slog.Schema{
+	{Name: fieldURL, Type: slog.StringOption, Default: "", Description: "endpoint URL"},
+	{Name: fieldMethod, Type: slog.StringOption, Default: "POST", Description: "HTTP method"},
+	{Name: fieldFormat, Type: slog.StringOption, Default: defaultFormat, Description: "payload format",
+		Values: []string{"loki", "elasticsearch", "webhook"}},
+	{Name: fieldLabels, Type: slog.OptionsOption, Description: "static stream labels (loki)"},
+	{Name: fieldIndex, Type: slog.StringOption, Default: "logs", Description: "index name (elasticsearch)"},
+	{Name: fieldHeaders, Type: slog.OptionsOption, Description: "additional request headers"},
+	{Name: fieldBatchSize, Type: slog.IntOption, Default: DefaultBatchSize, Description: "maximum entries per request"},
+	{Name: fieldBatchBytes, Type: slog.IntOption, Default: DefaultBatchBytes, Description: "approximate maximum bytes per request"},
+	{Name: fieldFlushInterval, Type: slog.DurationOption, Default: DefaultFlushInterval, Description: "maximum delay before pending entries are sent"},
+	{Name: fieldQueueSize, Type: slog.IntOption, Default: DefaultQueueSize, Description: "maximum pending entries, oldest are dropped"},
+	{Name: fieldGzip, Type: slog.BoolOption, Default: false, Description: "gzip request body"},
+	{Name: fieldMaxRetries, Type: slog.IntOption, Default: DefaultMaxRetries, Description: "retries on network error, 5xx and 429"},
+	{Name: fieldMinBackoff, Type: slog.DurationOption, Default: DefaultMinBackoff, Description: "initial retry delay"},
+	{Name: fieldMaxBackoff, Type: slog.DurationOption, Default: DefaultMaxBackoff, Description: "maximum retry delay"},
+	{Name: fieldTimeout, Type: slog.DurationOption, Default: DefaultTimeout, Description: "request timeout"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name written in each entry"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add caller to each entry"},
+	{Name: fieldOnError, Type: slog.ErrorHandlerOption, Description: "func(error) receiving send errors"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &httpConstructor{})
+}
+
+// New creates HTTP logger. Writer w is not used, entries are sent
+// in batch to the configured url. Call Flush/Close on the returned
+// *slog.HandlerLogger before the program exits.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := httpSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(_ io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ConfigFromOptions(op)
+	if err != nil {
+		return nil, err
+	}
+	h, err := NewHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lg := slog.NewHandlerLogger(h, 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// convert options into string map
+func stringMap(op slog.Options) map[string]string {
+	if len(op) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(op))
+	for k, v := range op {
+		m[k], _ = slog.AsString(v)
+	}
+	return m
+}
+
+// NewPayloadEncoder creates payload encoder from format option
+func NewPayloadEncoder(op slog.Options) (PayloadEncoder, error) {
+	switch format := strings.ToLower(op.GetString(fieldFormat, defaultFormat)); format {
+	case "loki":
+		return &LokiEncoder{Labels: stringMap(op.GetOptions(fieldLabels))}, nil
+	case "elasticsearch":
+		return &ElasticEncoder{Index: op.GetString(fieldIndex, "logs")}, nil
+	case "webhook":
+		return &WebhookEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("httplog: unknown format %q", format)
+	}
+}
+
+// ConfigFromOptions creates handler configuration from options
+func ConfigFromOptions(op slog.Options) (Config, error) {
+	enc, err := NewPayloadEncoder(op)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{
+		URL:           op.GetString(fieldURL, ""),
+		Method:        op.GetString(fieldMethod, "POST"),
+		Headers:       stringMap(op.GetOptions(fieldHeaders)),
+		Encoder:       enc,
+		Gzip:          op.GetBool(fieldGzip, false),
+		BatchSize:     op.GetInt(fieldBatchSize, DefaultBatchSize),
+		BatchBytes:    op.GetInt(fieldBatchBytes, DefaultBatchBytes),
+		FlushInterval: op.GetDuration(fieldFlushInterval, DefaultFlushInterval),
+		QueueSize:     op.GetInt(fieldQueueSize, DefaultQueueSize),
+		MaxRetries:    op.GetInt(fieldMaxRetries, DefaultMaxRetries),
+		MinBackoff:    op.GetDuration(fieldMinBackoff, DefaultMinBackoff),
+		MaxBackoff:    op.GetDuration(fieldMaxBackoff, DefaultMaxBackoff),
+	}
+	cfg.Client = &http.Client{Timeout: op.GetDuration(fieldTimeout, DefaultTimeout)}
+	onError, err := op.GetErrorHandler(fieldOnError)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.OnError = onError
+
+	return cfg, nil
+}
+
+func (c *httpConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *httpConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by HTTP logger
+func (c *httpConstructor) Schema() slog.Schema {
+	return httpSchema
+}
@@ -0,0 +1,182 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Record is entry encoded when it is handled, together with
+// entry attributes used to group records in the payload
+type Record struct {
+	Time   time.Time
+	Level  slog.Level
+	Logger string
+	Data   []byte
+}
+
+// PayloadEncoder encodes entries into request body. Each entry is encoded by
+// EncodeRecord when it is handled (the entry is not kept), queued records
+// are combined into request body by EncodePayload.
+type PayloadEncoder interface {
+	ContentType() string
+	EncodeRecord(buf *bytes.Buffer, e *slog.Entry) error
+	EncodePayload(buf *bytes.Buffer, records []Record) error
+}
+
+// LokiEncoder encodes entries as Loki push API JSON.
+// Entries are grouped into streams by static labels and `level` label,
+// each line is formatted using Line encoder (logfmt by default).
+type LokiEncoder struct {
+	Labels map[string]string
+	Line   slog.Encoder
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// ContentType of Loki push request
+func (enc *LokiEncoder) ContentType() string {
+	return "application/json"
+}
+
+// EncodeRecord writes log line of the entry
+func (enc *LokiEncoder) EncodeRecord(buf *bytes.Buffer, e *slog.Entry) error {
+	line := enc.Line
+	if line == nil {
+		line = &slog.LogfmtEncoder{}
+	}
+	n := buf.Len()
+	if err := line.Encode(buf, e); err != nil {
+		return err
+	}
+	buf.Truncate(n + len(bytes.TrimRight(buf.Bytes()[n:], "\n")))
+	return nil
+}
+
+// EncodePayload of records
+func (enc *LokiEncoder) EncodePayload(buf *bytes.Buffer, records []Record) error {
+	streams := make(map[string]*lokiStream)
+	for _, r := range records {
+		level := r.Level.String()
+		st, ok := streams[level]
+		if !ok {
+			labels := make(map[string]string, len(enc.Labels)+1)
+			for k, v := range enc.Labels {
+				labels[k] = v
+			}
+			labels["level"] = level
+			st = &lokiStream{Stream: labels}
+			streams[level] = st
+		}
+		ts := strconv.FormatInt(r.Time.UnixNano(), 10)
+		st.Values = append(st.Values, [2]string{ts, string(r.Data)})
+	}
+
+	// deterministic order of streams
+	keys := make([]string, 0, len(streams))
+	for k := range streams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, k := range keys {
+		payload.Streams = append(payload.Streams, streams[k])
+	}
+
+	return json.NewEncoder(buf).Encode(payload)
+}
+
+// ElasticEncoder encodes entries as Elasticsearch _bulk NDJSON,
+// each document is formatted using Document encoder (JSON by default).
+type ElasticEncoder struct {
+	Index    string
+	Document slog.Encoder
+}
+
+// ContentType of bulk request
+func (enc *ElasticEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// EncodeRecord writes document line of the entry
+func (enc *ElasticEncoder) EncodeRecord(buf *bytes.Buffer, e *slog.Entry) error {
+	doc := enc.Document
+	if doc == nil {
+		doc = &slog.JSONEncoder{}
+	}
+	n := buf.Len()
+	if err := doc.Encode(buf, e); err != nil {
+		return err
+	}
+	if buf.Len() == n || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// EncodePayload of records, `_index` is omitted when Index is empty
+// (i.e. index is given in the URL)
+func (enc *ElasticEncoder) EncodePayload(buf *bytes.Buffer, records []Record) error {
+	meta := map[string]string{}
+	if enc.Index != "" {
+		meta["_index"] = enc.Index
+	}
+	action, err := json.Marshal(map[string]interface{}{"index": meta})
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(r.Data)
+	}
+	return nil
+}
+
+// WebhookEncoder encodes entries as JSON array,
+// each element is formatted using Element encoder (JSON by default),
+// which must write JSON object (see slog.IsJSONEncoder).
+type WebhookEncoder struct {
+	Element slog.Encoder
+}
+
+// ContentType of webhook request
+func (enc *WebhookEncoder) ContentType() string {
+	return "application/json"
+}
+
+// EncodeRecord writes array element of the entry
+func (enc *WebhookEncoder) EncodeRecord(buf *bytes.Buffer, e *slog.Entry) error {
+	elem := enc.Element
+	if elem == nil {
+		elem = &slog.JSONEncoder{}
+	}
+	n := buf.Len()
+	if err := elem.Encode(buf, e); err != nil {
+		return err
+	}
+	buf.Truncate(n + len(bytes.TrimRight(buf.Bytes()[n:], "\n")))
+	return nil
+}
+
+// EncodePayload of records
+func (enc *WebhookEncoder) EncodePayload(buf *bytes.Buffer, records []Record) error {
+	buf.WriteByte('[')
+	for i, r := range records {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(r.Data)
+	}
+	buf.WriteString("]\n")
+	return nil
+}
@@ -0,0 +1,97 @@
+package httplog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+func TestPayloadEncoders(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	entries := []*slog.Entry{
+		{Time: ts, Level: slog.InfoLevel, Message: "started"},
+		{Time: ts.Add(time.Second), Level: slog.ErrorLevel, Message: "failed", Fields: slog.Fields{{Key: "n", Value: 1}}},
+		{Time: ts.Add(2 * time.Second), Level: slog.InfoLevel, Message: "done"},
+	}
+
+	tests := []struct {
+		name string
+		enc  PayloadEncoder
+		want string
+	}{
+		{
+			"webhook",
+			&WebhookEncoder{Element: &slog.JSONEncoder{TimestampFormat: slog.TimestampNone}},
+			`[{"level":"info","msg":"started"},{"level":"error","msg":"failed","n":1},{"level":"info","msg":"done"}]` + "\n",
+		},
+		{
+			"elasticsearch",
+			&ElasticEncoder{Index: "logs", Document: &slog.JSONEncoder{TimestampFormat: slog.TimestampNone}},
+			`{"index":{"_index":"logs"}}` + "\n" + `{"level":"info","msg":"started"}` + "\n" +
+				`{"index":{"_index":"logs"}}` + "\n" + `{"level":"error","msg":"failed","n":1}` + "\n" +
+				`{"index":{"_index":"logs"}}` + "\n" + `{"level":"info","msg":"done"}` + "\n",
+		},
+		{
+			"elasticsearch without index",
+			&ElasticEncoder{Document: &slog.JSONEncoder{TimestampFormat: slog.TimestampNone}},
+			`{"index":{}}` + "\n" + `{"level":"info","msg":"started"}` + "\n" +
+				`{"index":{}}` + "\n" + `{"level":"error","msg":"failed","n":1}` + "\n" +
+				`{"index":{}}` + "\n" + `{"level":"info","msg":"done"}` + "\n",
+		},
+		{
+			"loki",
+			&LokiEncoder{Labels: map[string]string{"app": "api"}, Line: &slog.LogfmtEncoder{TimestampFormat: slog.TimestampNone}},
+			`{"streams":[` +
+				`{"stream":{"app":"api","level":"error"},"values":[["1714979290000000000","level=error msg=failed n=1"]]},` +
+				`{"stream":{"app":"api","level":"info"},"values":[["1714979289000000000","level=info msg=started"],` +
+				`["1714979291000000000","level=info msg=done"]]}]}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []Record
+			for _, e := range entries {
+				var buf bytes.Buffer
+				if err := tt.enc.EncodeRecord(&buf, e); err != nil {
+					t.Fatal(err)
+				}
+				records = append(records, Record{Time: e.Time, Level: e.Level, Logger: e.Logger, Data: buf.Bytes()})
+			}
+			var buf bytes.Buffer
+			if err := tt.enc.EncodePayload(&buf, records); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookElementEncoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		elem    slog.Encoder
+		wantErr bool
+	}{
+		{"default", nil, false},
+		{"json", &slog.JSONEncoder{}, false},
+		{"ecs", &slog.ECSEncoder{}, false},
+		{"limited json", slog.LimitEncoder(&slog.JSONEncoder{}, 100), false},
+		{"logfmt", &slog.LogfmtEncoder{}, true},
+		{"text", &slog.TextEncoder{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler(Config{URL: "http://127.0.0.1:1", Encoder: &WebhookEncoder{Element: tt.elem}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if h != nil {
+				h.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Conn sends entries to journal socket as datagram.
+// Payload which is too large for a datagram is passed as sealed memfd
+// (or unlinked file in /dev/shm), the socket is recreated and write is retried once on error.
+type Conn struct {
+	mu   sync.Mutex
+	addr *net.UnixAddr
+	conn *net.UnixConn
+}
+
+// Dial journal socket with given path, the path is checked when writing
+func Dial(path string) (*Conn, error) {
+	c := &Conn{addr: &net.UnixAddr{Name: path, Net: "unixgram"}}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// socket is not connected, each entry is sent to the journal address
+func (c *Conn) connect() error {
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+func (c *Conn) write(p []byte) error {
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	_, _, err := c.conn.WriteMsgUnix(p, nil, c.addr)
+	if isTooLarge(err) {
+		err = c.writeFd(p)
+	}
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+func isTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// pass payload as file descriptor
+func (c *Conn) writeFd(p []byte) error {
+	f, err := memFile(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rights := syscall.UnixRights(int(f.Fd()))
+	_, _, err = c.conn.WriteMsgUnix(nil, rights, c.addr)
+	return err
+}
+
+// memFile return sealed memfd containing p, or unlinked temporary file if memfd is not available
+func memFile(p []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate("journal-entry", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err == nil {
+		f := os.NewFile(uintptr(fd), "journal-entry")
+		if _, err := f.Write(p); err != nil {
+			f.Close()
+			return nil, err
+		}
+		seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+		if _, err := unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return f, nil
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "journal-entry-")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	if _, err := f.Write(p); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write single entry, reconnect and retry once on error
+func (c *Conn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.write(p); err != nil {
+		if err = c.write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close the connection
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,110 @@
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// listenJournal creates unixgram socket standing in for the journal
+func listenJournal(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "socket")
+	ln, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return path, ln
+}
+
+// readJournal reads one datagram, payload passed as file descriptor is read from the file
+func readJournal(t *testing.T, ln *net.UnixConn) []byte {
+	t.Helper()
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := ln.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oobn == 0 {
+		return buf[:n]
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("invalid control message: %v", err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("invalid unix rights: %v", err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "journal-entry")
+	defer f.Close()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	p, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestJournalSocket(t *testing.T) {
+	path, ln := listenJournal(t)
+	lg, err := New(nil, slog.TraceLevel, slog.Options{
+		"socket":       path,
+		"identifier":   "app",
+		"name":         "api",
+		"reportCaller": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.(*slog.HandlerLogger).Close()
+
+	large := strings.Repeat("x", 1<<20)
+	tests := []struct {
+		name string
+		log  func()
+		want map[string]string
+	}{
+		{"info", func() { lg.Infow("started", "port", 8080) },
+			map[string]string{"MESSAGE": "started", "PRIORITY": "6", "SYSLOG_IDENTIFIER": "app",
+				"LOGGER": "api", "PORT": "8080"}},
+		{"error", func() { lg.Errorw("failed", "err", "timeout\nretry") },
+			map[string]string{"MESSAGE": "failed", "PRIORITY": "3", "ERR": "timeout\nretry"}},
+		{"debug", func() { lg.Debug("tick") },
+			map[string]string{"MESSAGE": "tick", "PRIORITY": "7"}},
+		{"payload passed as memfd", func() { lg.Infow("large", "data", large) },
+			map[string]string{"MESSAGE": "large", "DATA": large}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log()
+			got := parseNative(t, readJournal(t, ln))
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %.40q, want %.40q", k, got[k], v)
+				}
+			}
+			for _, k := range []string{FieldCodeFile, FieldCodeLine, FieldCodeFunc} {
+				if _, ok := got[k]; !ok {
+					t.Errorf("%s missing", k)
+				}
+			}
+		})
+	}
+}
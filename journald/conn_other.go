@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package journald
+
+import "errors"
+
+// Conn sends entries to journal socket, only supported in linux
+type Conn struct{}
+
+// Dial journal socket, always fails outside linux
+func Dial(path string) (*Conn, error) {
+	return nil, errors.New("journald: not supported in this platform")
+}
+
+// Write is not supported
+func (c *Conn) Write(p []byte) (int, error) {
+	return 0, errors.New("journald: not supported in this platform")
+}
+
+// Close the connection
+func (c *Conn) Close() error {
+	return nil
+}
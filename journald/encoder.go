@@ -0,0 +1,97 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/syslog"
+)
+
+// Journal field names written by the encoder
+const (
+	FieldMessage    = "MESSAGE"
+	FieldPriority   = "PRIORITY"
+	FieldCodeFile   = "CODE_FILE"
+	FieldCodeLine   = "CODE_LINE"
+	FieldCodeFunc   = "CODE_FUNC"
+	FieldIdentifier = "SYSLOG_IDENTIFIER"
+	FieldLogger     = "LOGGER"
+)
+
+// prefix of user field which clashes with field written by encoder
+const clashPrefix = "FIELD_"
+
+// Encoder writes entry using journal native protocol, i.e. NAME=value lines,
+// or NAME, 64-bit little endian length and value for values containing new line.
+type Encoder struct {
+	// Identifier written as SYSLOG_IDENTIFIER, omitted if empty
+	Identifier string
+}
+
+func isStdField(name string) bool {
+	switch name {
+	case FieldMessage, FieldPriority, FieldCodeFile, FieldCodeLine, FieldCodeFunc, FieldIdentifier, FieldLogger:
+		return true
+	}
+	return false
+}
+
+// FieldName converts key into valid journal field name:
+// uppercase A-Z, 0-9 and underscore, not starting with underscore or digit, at most 64 chars.
+func FieldName(key string) string {
+	b := []byte(strings.ToUpper(key))
+	for i, c := range b {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	name := strings.TrimLeft(string(b), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || isStdField(name) {
+		name = clashPrefix + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// Encode entry
+func (enc *Encoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	writeField(buf, FieldMessage, e.Message)
+	writeField(buf, FieldPriority, strconv.Itoa(syslog.Severity(e.Level)))
+	if enc.Identifier != "" {
+		writeField(buf, FieldIdentifier, enc.Identifier)
+	}
+	if e.Logger != "" {
+		writeField(buf, FieldLogger, e.Logger)
+	}
+	if e.Caller != nil {
+		writeField(buf, FieldCodeFile, e.Caller.File)
+		writeField(buf, FieldCodeLine, strconv.Itoa(e.Caller.Line))
+		writeField(buf, FieldCodeFunc, e.Caller.Function)
+	}
+	for _, f := range slog.FlattenFields(e.Fields) {
+		str, _ := slog.AsString(f.Value)
+		writeField(buf, FieldName(f.Key), str)
+	}
+
+	return nil
+}
+
+func writeField(buf *bytes.Buffer, name, val string) {
+	buf.WriteString(name)
+	if strings.IndexByte(val, '\n') < 0 {
+		buf.WriteByte('=')
+		buf.WriteString(val)
+	} else {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(val)))
+		buf.WriteByte('\n')
+		buf.Write(size[:])
+		buf.WriteString(val)
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,126 @@
+package journald
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ipsusila/slog"
+)
+
+// parseNative decodes journal native protocol payload
+func parseNative(t *testing.T, p []byte) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	r := bufio.NewReader(bytes.NewReader(p))
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return fields
+		}
+		if err != nil {
+			t.Fatalf("invalid payload %q: %v", p, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if i := strings.IndexByte(line, '='); i >= 0 {
+			fields[line[:i]] = line[i+1:]
+			continue
+		}
+		var size [8]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			t.Fatalf("missing size of %s: %v", line, err)
+		}
+		val := make([]byte, binary.LittleEndian.Uint64(size[:])+1)
+		if _, err := io.ReadFull(r, val); err != nil || val[len(val)-1] != '\n' {
+			t.Fatalf("invalid value of %s: %v", line, err)
+		}
+		fields[line] = string(val[:len(val)-1])
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_id", "USER_ID"},
+		{"http.method", "HTTP_METHOD"},
+		{"_private", "PRIVATE"},
+		{"1st", "FIELD_1ST"},
+		{"", "FIELD_"},
+		{"message", "FIELD_MESSAGE"},
+		{"priority", "FIELD_PRIORITY"},
+		{strings.Repeat("a", 70), strings.Repeat("A", 64)},
+	}
+	for _, tt := range tests {
+		if got := FieldName(tt.key); got != tt.want {
+			t.Errorf("FieldName(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name  string
+		enc   *Encoder
+		entry func() *slog.Entry
+		want  map[string]string
+	}{
+		{
+			"message and priority",
+			&Encoder{},
+			func() *slog.Entry { return slog.NewEntry(slog.WarnLevel, "disk low", nil) },
+			map[string]string{"MESSAGE": "disk low", "PRIORITY": "4"},
+		},
+		{
+			"identifier, logger and fields",
+			&Encoder{Identifier: "app"},
+			func() *slog.Entry {
+				e := slog.NewEntry(slog.ErrorLevel, "failed", []interface{}{"user.id", 7, "message", "clash"})
+				e.Logger = "db"
+				return e
+			},
+			map[string]string{"MESSAGE": "failed", "PRIORITY": "3", "SYSLOG_IDENTIFIER": "app",
+				"LOGGER": "db", "USER_ID": "7", "FIELD_MESSAGE": "clash"},
+		},
+		{
+			"caller",
+			&Encoder{},
+			func() *slog.Entry {
+				e := slog.NewEntry(slog.InfoLevel, "ok", nil)
+				e.Caller = &slog.Caller{Function: "main.run", File: "main.go", Line: 12}
+				return e
+			},
+			map[string]string{"MESSAGE": "ok", "PRIORITY": "6", "CODE_FILE": "main.go",
+				"CODE_LINE": "12", "CODE_FUNC": "main.run"},
+		},
+		{
+			"multi-line values",
+			&Encoder{},
+			func() *slog.Entry {
+				return slog.NewEntry(slog.DebugLevel, "line 1\nline 2", []interface{}{"stack", "a\nb=c\n"})
+			},
+			map[string]string{"MESSAGE": "line 1\nline 2", "PRIORITY": "7", "STACK": "a\nb=c\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.enc.Encode(&buf, tt.entry()); err != nil {
+				t.Fatal(err)
+			}
+			got := parseNative(t, buf.Bytes())
+			if len(got) != len(tt.want) {
+				t.Errorf("fields %q, want %q", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
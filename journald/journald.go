@@ -0,0 +1,98 @@
+package journald
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ipsusila/slog"
+)
+
+// Name of journald logger
+const Name = "journald"
+
+// DefaultSocket is path of journal native protocol socket
+const DefaultSocket = "/run/systemd/journal/socket"
+
+type journaldConstructor struct{}
+
+const (
+	fieldSocket       = "socket"
+	fieldIdentifier   = "identifier"
+	fieldName         = "name"
+	fieldReportCaller = "reportCaller"
+)
+
+// options supported by journald logger
+var journaldSchema = slog.Schema{
+	{Name: fieldSocket, Type: slog.StringOption, Default: DefaultSocket, Description: "journal socket path"},
+	{Name: fieldIdentifier, Type: slog.StringOption, Description: "SYSLOG_IDENTIFIER, default to program name"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name, written as LOGGER"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "write CODE_FILE, CODE_LINE and CODE_FUNC"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &journaldConstructor{})
+}
+
+// New creates journald logger. If w is not nil, entries are written to w,
+// otherwise entries are sent to journal socket.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := journaldSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		conn, err := Dial(op.GetString(fieldSocket, DefaultSocket))
+		if err != nil {
+			return nil, err
+		}
+		w = conn
+	}
+
+	ident := op.GetString(fieldIdentifier, "")
+	if ident == "" {
+		ident = filepath.Base(os.Args[0])
+	}
+	enc := &Encoder{Identifier: ident}
+
+	lg := slog.NewHandlerLogger(slog.NewWriterHandler(w, enc), 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+func (c *journaldConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *journaldConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by journald logger
+func (c *journaldConstructor) Schema() slog.Schema {
+	return journaldSchema
+}
@@ -0,0 +1,43 @@
+package slog
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LazyValue is evaluated when it is logged, i.e. only if the level is enabled.
+// The function is called at most once, the result is shared by all sinks.
+type LazyValue struct {
+	once sync.Once
+	fn   func() interface{}
+	val  interface{}
+}
+
+// Lazy creates value evaluated by fn when logged
+func Lazy(fn func() interface{}) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Lazyf creates string value formatted with fmt.Sprintf when logged,
+// can be used as field value or message argument
+func Lazyf(format string, args ...interface{}) *LazyValue {
+	return Lazy(func() interface{} {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+// LogValue evaluates the function once
+func (lv *LazyValue) LogValue() interface{} {
+	lv.once.Do(func() {
+		if lv.fn != nil {
+			lv.val = lv.fn()
+		}
+	})
+	return lv.val
+}
+
+// String return evaluated value as string, used when formatting message
+func (lv *LazyValue) String() string {
+	str, _ := AsString(lv)
+	return str
+}
@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLazyDisabledLevel(t *testing.T) {
+	calls := 0
+	fn := func() interface{} { calls++; return "expensive" }
+	hl := NewHandlerLogger(&entryRecorder{}, InfoLevel)
+
+	hl.Debugw("field", "v", Lazy(fn))
+	hl.Debugf("argument %v", Lazy(fn))
+	hl.Debug("value", Lazy(fn))
+	if calls != 0 {
+		t.Errorf("function called %d times for disabled level", calls)
+	}
+}
+
+func TestLazyOnceAcrossSinks(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg Logger, v *LazyValue)
+	}{
+		{"field", func(lg Logger, v *LazyValue) { lg.Infow("msg", "v", v) }},
+		{"message argument", func(lg Logger, v *LazyValue) { lg.Infof("msg %v", v) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			v := Lazy(func() interface{} { calls++; return "expensive" })
+			var jsonBuf, logfmtBuf, textBuf bytes.Buffer
+			h := MultiHandler(
+				NewWriterHandler(&jsonBuf, &JSONEncoder{TimestampFormat: TimestampNone}),
+				NewWriterHandler(&logfmtBuf, &LogfmtEncoder{TimestampFormat: TimestampNone}),
+				NewWriterHandler(&textBuf, &TextEncoder{TimestampFormat: TimestampNone, DisableColor: true}),
+			)
+			tt.log(NewHandlerLogger(h, InfoLevel), v)
+			if calls != 1 {
+				t.Errorf("function called %d times, want once", calls)
+			}
+			for _, out := range []string{jsonBuf.String(), logfmtBuf.String(), textBuf.String()} {
+				if !strings.Contains(out, "expensive") {
+					t.Errorf("value not written: %q", out)
+				}
+			}
+		})
+	}
+}
+
+func TestLazyf(t *testing.T) {
+	v := Lazyf("%s-%d", "id", 7)
+	if got := v.String(); got != "id-7" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -3,7 +3,10 @@ package slog
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Level of the log
@@ -22,44 +25,132 @@ const (
 	TraceLevel
 )
 
-// AllLevel contains all log level
+// AllLevel contains all built-in log level
 const AllLevel = PanicLevel | FatalLevel | ErrorLevel | WarnLevel | InfoLevel | DebugLevel | TraceLevel
 
-var lvAll = []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel, InfoLevel, DebugLevel, TraceLevel}
+// Severity of built-in levels, user defined level may be placed in between
+const (
+	TraceSeverity = 100 * (iota + 1)
+	DebugSeverity
+	InfoSeverity
+	WarnSeverity
+	ErrorSeverity
+	FatalSeverity
+	PanicSeverity
+)
 
-var lvStrMap = map[Level]string{
-	PanicLevel: "panic",
-	FatalLevel: "fatal",
-	ErrorLevel: "error",
-	WarnLevel:  "warn",
-	InfoLevel:  "info",
-	DebugLevel: "debug",
-	TraceLevel: "trace",
-	AllLevel:   "all",
+// LevelSpec describes user defined level.
+// Name is used by ParseLevel/MarshalText, Fixed is fixed width string used in text output,
+// Color is optional style in colored text output (see ParseColor) and Severity determines relative position
+// of the level against other levels (higher is more severe).
+type LevelSpec struct {
+	Name     string
+	Fixed    string
+	Color    Color
+	Severity int
 }
 
-// convert level to fixed string
-var lvFixStrMap = map[Level]string{
-	PanicLevel: "PANIC",
-	FatalLevel: "FATAL",
-	ErrorLevel: "ERROR",
-	WarnLevel:  "WARNN",
-	InfoLevel:  "INFOO",
-	DebugLevel: "DEBUG",
-	TraceLevel: "TRACE",
+// registered levels, replaced as a whole when new level is registered
+type levelTable struct {
+	all    []Level
+	specs  map[Level]LevelSpec
+	byName map[string]Level
+	next   Level
+	// generation, incremented by each registration
+	gen uint32
 }
 
-//
+var (
+	levelsMu sync.Mutex
+	levels   = builtinLevels()
+)
+
+func builtinLevels() *atomic.Value {
+	builtins := []LevelSpec{
+		{Name: "panic", Fixed: "PANIC", Severity: PanicSeverity},
+		{Name: "fatal", Fixed: "FATAL", Severity: FatalSeverity},
+		{Name: "error", Fixed: "ERROR", Severity: ErrorSeverity},
+		{Name: "warn", Fixed: "WARNN", Severity: WarnSeverity},
+		{Name: "info", Fixed: "INFOO", Severity: InfoSeverity},
+		{Name: "debug", Fixed: "DEBUG", Severity: DebugSeverity},
+		{Name: "trace", Fixed: "TRACE", Severity: TraceSeverity},
+	}
+	lt := &levelTable{
+		specs:  make(map[Level]LevelSpec),
+		byName: make(map[string]Level),
+	}
+	for i, spec := range builtins {
+		lv := Level(1 << i)
+		lt.all = append(lt.all, lv)
+		lt.specs[lv] = spec
+		lt.byName[spec.Name] = lv
+	}
+	lt.next = TraceLevel << 1
+
+	v := &atomic.Value{}
+	v.Store(lt)
+	return v
+}
 
-var strLvMap = map[string]Level{
-	"panic": PanicLevel,
-	"fatal": FatalLevel,
-	"error": ErrorLevel,
-	"warn":  WarnLevel,
-	"info":  InfoLevel,
-	"debug": DebugLevel,
-	"trace": TraceLevel,
-	"all":   AllLevel,
+func currentLevels() *levelTable {
+	return levels.Load().(*levelTable)
+}
+
+// RegisterLevel registers user defined level and return its flag.
+func RegisterLevel(spec LevelSpec) (Level, error) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	spec.Name = strings.ToLower(spec.Name)
+	if spec.Name == "" || spec.Name == "all" || strings.Contains(spec.Name, lvSep) {
+		return 0, errors.New("invalid level name: " + spec.Name)
+	}
+	if spec.Fixed == "" {
+		spec.Fixed = strings.ToUpper(spec.Name)
+	}
+
+	cur := currentLevels()
+	if _, dup := cur.byName[spec.Name]; dup {
+		return 0, errors.New("level already registered: " + spec.Name)
+	}
+	if cur.next == 0 {
+		return 0, errors.New("too many levels")
+	}
+
+	// copy current table
+	lv := cur.next
+	lt := &levelTable{
+		all:    append([]Level{lv}, cur.all...),
+		specs:  make(map[Level]LevelSpec, len(cur.specs)+1),
+		byName: make(map[string]Level, len(cur.byName)+1),
+		next:   cur.next << 1,
+		gen:    cur.gen + 1,
+	}
+	for k, v := range cur.specs {
+		lt.specs[k] = v
+	}
+	for k, v := range cur.byName {
+		lt.byName[k] = v
+	}
+	lt.specs[lv] = spec
+	lt.byName[spec.Name] = lv
+
+	// most severe first
+	sort.SliceStable(lt.all, func(i, j int) bool {
+		return lt.specs[lt.all[i]].Severity > lt.specs[lt.all[j]].Severity
+	})
+	levels.Store(lt)
+
+	return lv, nil
+}
+
+// MustRegisterLevel registers user defined level, panics on error
+func MustRegisterLevel(spec LevelSpec) Level {
+	lv, err := RegisterLevel(spec)
+	if err != nil {
+		panic(err)
+	}
+	return lv
 }
 
 // Has specific level
@@ -82,6 +173,43 @@ func (l *Level) Toggle(lv Level) {
 	*l = *l ^ lv
 }
 
+// Severity of the least severe level in the flags, 0 for unknown level
+func (l Level) Severity() int {
+	lt := currentLevels()
+	sev := 0
+	for _, lv := range lt.all {
+		if l.Has(lv) {
+			sev = lt.specs[lv].Severity
+		}
+	}
+	return sev
+}
+
+// Spec return specification of a single level
+func (l Level) Spec() (LevelSpec, bool) {
+	spec, ok := currentLevels().specs[l]
+	return spec, ok
+}
+
+// Builtin return built-in level nearest to the given level,
+// i.e. the most severe built-in level which is not more severe than l.
+func (l Level) Builtin() Level {
+	if AllLevel.Has(l) && l&^AllLevel == 0 && l&(l-1) == 0 {
+		return l
+	}
+	lt := currentLevels()
+	sev := l.Severity()
+	for _, lv := range lvBuiltin {
+		if lt.specs[lv].Severity <= sev {
+			return lv
+		}
+	}
+	return TraceLevel
+}
+
+// built-in levels, most severe first
+var lvBuiltin = []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel, InfoLevel, DebugLevel, TraceLevel}
+
 // Stringer interface
 func (l Level) String() string {
 	if b, err := l.MarshalText(); err == nil {
@@ -91,28 +219,32 @@ func (l Level) String() string {
 	}
 }
 
-// Levels return all level
+// Levels return all level, most severe first
 func Levels() []Level {
-	return lvAll
+	all := currentLevels().all
+	lvs := make([]Level, len(all))
+	copy(lvs, all)
+	return lvs
 }
 
 // LevelCount return number of valid log levels
 func LevelsCount() int {
-	return len(lvAll)
+	return len(currentLevels().all)
 }
 
 // LevelFixedString return fixed string for given level
 func LevelFixedString(lv Level) string {
-	if str, ok := lvFixStrMap[lv]; ok {
-		return str
+	if spec, ok := currentLevels().specs[lv]; ok {
+		return spec.Fixed
 	}
 	return "OTHER"
 }
 
 // ParseLevel string
 func ParseLevel(level string) (Level, error) {
+	lt := currentLevels()
 	lvStr := strings.ToLower(level)
-	lv, ok := strLvMap[lvStr]
+	lv, ok := lt.levelOf(lvStr)
 	if ok {
 		return lv, nil
 	}
@@ -121,7 +253,7 @@ func ParseLevel(level string) (Level, error) {
 	lv = 0
 	levels := strings.Split(lvStr, lvSep)
 	for _, str := range levels {
-		if v, ok := strLvMap[str]; ok {
+		if v, ok := lt.levelOf(str); ok {
 			lv.Set(v)
 		}
 	}
@@ -133,6 +265,18 @@ func ParseLevel(level string) (Level, error) {
 	return lv, nil
 }
 
+func (lt *levelTable) levelOf(name string) (Level, bool) {
+	if name == "all" {
+		var lv Level
+		for _, l := range lt.all {
+			lv.Set(l)
+		}
+		return lv, true
+	}
+	lv, ok := lt.byName[name]
+	return lv, ok
+}
+
 // UnmarshalText implements encoding.TextUnmarshaler.
 func (l *Level) UnmarshalText(text []byte) error {
 	lv, err := ParseLevel(string(text))
@@ -146,13 +290,14 @@ func (l *Level) UnmarshalText(text []byte) error {
 
 // MarshalText return level as byte string
 func (l Level) MarshalText() ([]byte, error) {
+	lt := currentLevels()
 	sb := strings.Builder{}
-	for i, lv := range lvAll {
+	for _, lv := range lt.all {
 		if l.Has(lv) {
-			if i > 0 {
+			if sb.Len() > 0 {
 				sb.WriteString(lvSep)
 			}
-			sb.WriteString(lvStrMap[lv])
+			sb.WriteString(lt.specs[lv].Name)
 		}
 	}
 	if sb.Len() == 0 {
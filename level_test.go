@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegisterLevelAfterLoggerCreated(t *testing.T) {
+	var buf bytes.Buffer
+	threshold, _ := NewStdLogger(&buf, TraceLevel, Options{"formatter": "logfmt"})
+	mask, _ := NewStdLogger(&buf, InfoLevel, Options{"levelMode": "mask"})
+
+	audit := MustRegisterLevel(LevelSpec{Name: "audit-late", Severity: InfoSeverity + 50})
+	verbose := MustRegisterLevel(LevelSpec{Name: "verbose-late", Severity: TraceSeverity - 50})
+
+	tests := []struct {
+		name string
+		l    Logger
+		lv   Level
+		want bool
+	}{
+		{"threshold enables more severe", threshold, audit, true},
+		{"threshold skips less severe", threshold, verbose, false},
+		{"default logger", Default(), audit, true},
+		{"mask unchanged", mask, audit, false},
+		{"mask keeps info", mask, InfoLevel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.HasLevel(tt.lv); got != tt.want {
+				t.Errorf("HasLevel(%v) = %v, want %v", tt.lv, got, tt.want)
+			}
+		})
+	}
+
+	Leveled(threshold).Logw(audit, "recorded")
+	if !strings.Contains(buf.String(), "level=audit-late") {
+		t.Errorf("audit entry not written: %q", buf.String())
+	}
+}
@@ -1,33 +1,191 @@
 package slog
 
-// LevelLogger base
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelMode determines how level flags given to SetLevel are interpreted
+type LevelMode uint32
+
+// Level modes
+const (
+	// ThresholdMode enables given levels and all levels which are more severe
+	ThresholdMode LevelMode = iota
+	// MaskMode enables exactly the levels given in the flags
+	MaskMode
+)
+
+// Option name for level mode, shared by all loggers
+const fieldLevelMode = "levelMode"
+
+// OptionLevelMode describes level mode option accepted by loggers
+var OptionLevelMode = OptionSpec{
+	Name:        fieldLevelMode,
+	Type:        StringOption,
+	Default:     "threshold",
+	Description: "level semantics, either threshold or mask",
+	Values:      []string{"threshold", "mask"},
+}
+
+var lvModeStrMap = map[LevelMode]string{
+	ThresholdMode: "threshold",
+	MaskMode:      "mask",
+}
+
+// LevelModer is implemented by logger which supports level modes
+type LevelModer interface {
+	LevelMode() LevelMode
+	SetLevelMode(m LevelMode)
+}
+
+// LevelLogger base, level can be accessed concurrently.
+// Threshold is expanded again when level is registered after SetLevel.
 type LevelLoggerBase struct {
-	level Level
+	mu        sync.Mutex
+	level     Level
+	requested Level
+	mode      LevelMode
+	// generation of level table used to expand the level
+	gen uint32
 }
 
-// NewLevelLoggerBase return new instance of level logger base
+// NewLevelLoggerBase return new instance of level logger base in threshold mode
 func NewLevelLoggerBase(lv Level) *LevelLoggerBase {
-	bl := &LevelLoggerBase{}
+	return NewLevelLoggerBaseMode(lv, ThresholdMode)
+}
+
+// NewLevelLoggerBaseMode return new instance of level logger base with given mode
+func NewLevelLoggerBaseMode(lv Level, m LevelMode) *LevelLoggerBase {
+	bl := &LevelLoggerBase{mode: m}
 	bl.SetLevel(lv)
 	return bl
 }
 
 // HasLevel return current logger level
 func (b *LevelLoggerBase) HasLevel(lv Level) bool {
-	return b.level.Has(lv)
+	return b.Level().Has(lv)
 }
 
-// SetLevel set logger level using flags
+// Level return current logger level flags
+func (b *LevelLoggerBase) Level() Level {
+	if atomic.LoadUint32(&b.gen) != currentLevels().gen {
+		b.mu.Lock()
+		b.apply(b.requested)
+		b.mu.Unlock()
+	}
+	return Level(atomic.LoadUint32((*uint32)(&b.level)))
+}
+
+// LevelMode return current level mode
+func (b *LevelLoggerBase) LevelMode() LevelMode {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.mode
+}
+
+// SetLevelMode change level mode and re-apply last requested level
+func (b *LevelLoggerBase) SetLevelMode(m LevelMode) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mode = m
+	b.apply(b.requested)
+}
+
+// SetLevel set logger level using flags.
+// In threshold mode, all levels more severe than the least severe flag are enabled.
 func (b *LevelLoggerBase) SetLevel(lv Level) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requested = lv
+	b.apply(lv)
+}
+
+func (b *LevelLoggerBase) apply(lv Level) {
+	lt := currentLevels()
+	if b.mode == ThresholdMode {
+		lv = lv.threshold(lt)
+	}
+	atomic.StoreUint32((*uint32)(&b.level), uint32(lv))
+	atomic.StoreUint32(&b.gen, lt.gen)
+}
+
+// Threshold expands level flags to include all levels which are more severe
+// than the least severe flag.
+func (l Level) Threshold() Level {
+	return l.threshold(currentLevels())
+}
+
+func (l Level) threshold(lt *levelTable) Level {
+	all := lt.all
 	lvlIdx := 0
-	for i, l := range lvAll {
-		if lv.Has(l) {
+	for i, lv := range all {
+		if l.Has(lv) {
 			lvlIdx = i
 		}
 	}
 	for i := 0; i < lvlIdx; i++ {
-		lv.Set(lvAll[i])
+		l.Set(all[i])
+	}
+	return l
+}
+
+// Stringer interface
+func (m LevelMode) String() string {
+	if str, ok := lvModeStrMap[m]; ok {
+		return str
+	}
+	return "unknown"
+}
+
+// ParseLevelMode string, either `threshold` or `mask`
+func ParseLevelMode(mode string) (LevelMode, error) {
+	str := strings.ToLower(mode)
+	for m, s := range lvModeStrMap {
+		if s == str {
+			return m, nil
+		}
+	}
+	return 0, errors.New("unknown level mode: " + mode)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *LevelMode) UnmarshalText(text []byte) error {
+	mode, err := ParseLevelMode(string(text))
+	if err != nil {
+		return err
+	}
+	*m = mode
+
+	return nil
+}
+
+// MarshalText return level mode as byte string
+func (m LevelMode) MarshalText() ([]byte, error) {
+	if str, ok := lvModeStrMap[m]; ok {
+		return []byte(str), nil
+	}
+	return nil, errors.New("unknown level mode")
+}
+
+// GetLevelMode return level mode stored in options with given key
+func (op Options) GetLevelMode(key string, def LevelMode) LevelMode {
+	val, ok := op[key]
+	if !ok {
+		return def
+	}
+	switch v := val.(type) {
+	case LevelMode:
+		return v
+	case *LevelMode:
+		return *v
+	}
+	if str, ok := toString(val); ok {
+		if m, err := ParseLevelMode(str); err == nil {
+			return m
+		}
 	}
-	b.level = 0
-	b.level.Set(lv)
+	return def
 }
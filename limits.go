@@ -0,0 +1,407 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// TruncatedKey is the key of field added in place of dropped fields or map entries
+const TruncatedKey = "_truncated"
+
+// Option names of size limits
+const (
+	fieldMaxMessageLength = "maxMessageLength"
+	fieldMaxValueLength   = "maxValueLength"
+	fieldMaxFields        = "maxFields"
+	fieldMaxElements      = "maxElements"
+	fieldMaxEntryBytes    = "maxEntryBytes"
+)
+
+// Size limit options, zero means unlimited
+var (
+	OptionMaxMessageLength = OptionSpec{Name: fieldMaxMessageLength, Type: IntOption, Default: 0,
+		Description: "maximum message length in bytes"}
+	OptionMaxValueLength = OptionSpec{Name: fieldMaxValueLength, Type: IntOption, Default: 0,
+		Description: "maximum length in bytes of string, []byte and error values"}
+	OptionMaxFields = OptionSpec{Name: fieldMaxFields, Type: IntOption, Default: 0,
+		Description: "maximum number of fields of an entry"}
+	OptionMaxElements = OptionSpec{Name: fieldMaxElements, Type: IntOption, Default: 0,
+		Description: "maximum number of elements of slice, map or group"}
+	OptionMaxEntryBytes = OptionSpec{Name: fieldMaxEntryBytes, Type: IntOption, Default: 0,
+		Description: "maximum size of encoded entry in bytes"}
+)
+
+// Limits bounds size of entry. Zero limit means unlimited.
+// Encoded entry size is limited separately by LimitEncoder.
+type Limits struct {
+	// MaxMessage is maximum message length in bytes
+	MaxMessage int
+	// MaxValue is maximum length in bytes of string, []byte and error values
+	MaxValue int
+	// MaxFields is maximum number of fields of an entry
+	MaxFields int
+	// MaxElements is maximum number of elements of slice, array, map or group
+	MaxElements int
+}
+
+// GetLimits return limits from maxMessageLength, maxValueLength, maxFields and maxElements options
+func (op Options) GetLimits() Limits {
+	return Limits{
+		MaxMessage:  op.GetInt(fieldMaxMessageLength, 0),
+		MaxValue:    op.GetInt(fieldMaxValueLength, 0),
+		MaxFields:   op.GetInt(fieldMaxFields, 0),
+		MaxElements: op.GetInt(fieldMaxElements, 0),
+	}
+}
+
+// TruncateString cuts s to at most n bytes (at rune boundary) followed by
+// marker, e.g. `…(truncated 12345 bytes)`. Non positive n means unlimited.
+func TruncateString(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	return truncate(s, n)
+}
+
+func truncate(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncatedMarker(len(s)-n, "bytes")
+}
+
+// TruncatedMarker return marker of n dropped units, e.g. `…(truncated 3 fields)`
+func TruncatedMarker(n int, unit string) string {
+	return "…(truncated " + strconv.Itoa(n) + " " + unit + ")"
+}
+
+// Apply limits to message and fields of the entry
+func (lm Limits) Apply(e *Entry) {
+	e.Message = TruncateString(e.Message, lm.MaxMessage)
+	e.Fields = lm.ApplyFields(e.Fields)
+}
+
+// ApplyFields limits number of fields and their values, fields may be modified in place.
+// Dropped fields are replaced with single TruncatedKey field.
+func (lm Limits) ApplyFields(fields []Field) []Field {
+	dropped := 0
+	if lm.MaxFields > 0 && len(fields) > lm.MaxFields {
+		dropped = len(fields) - lm.MaxFields
+		fields = fields[:lm.MaxFields:lm.MaxFields]
+	}
+	if lm.MaxValue > 0 || lm.MaxElements > 0 {
+		for i := range fields {
+			if v, ok := lm.value(fields[i].Value, 0); ok {
+				fields[i].Value = v
+			}
+		}
+	}
+	if dropped > 0 {
+		fields = append(fields, Field{Key: TruncatedKey, Value: TruncatedMarker(dropped, "fields")})
+	}
+	return fields
+}
+
+// value return limited value and true if it differs from val
+func (lm Limits) value(val interface{}, depth int) (interface{}, bool) {
+	switch v := val.(type) {
+	case nil:
+		return nil, false
+	case string:
+		if lm.MaxValue > 0 && len(v) > lm.MaxValue {
+			return truncate(v, lm.MaxValue), true
+		}
+		return v, false
+	case []byte:
+		if lm.MaxValue > 0 && len(v) > lm.MaxValue {
+			return truncate(string(v), lm.MaxValue), true
+		}
+		return v, false
+	case error:
+		if lm.MaxValue > 0 {
+			if msg := v.Error(); len(msg) > lm.MaxValue {
+				return &truncatedError{error: v, msg: truncate(msg, lm.MaxValue)}, true
+			}
+		}
+		return v, false
+	case Object:
+		return lm.object(v, depth)
+	}
+	if depth >= MaxValueDepth {
+		return val, false
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return lm.slice(rv, depth)
+	case reflect.Map:
+		return lm.mapValue(rv, depth)
+	}
+	return val, false
+}
+
+func (lm Limits) object(o Object, depth int) (interface{}, bool) {
+	if depth >= MaxValueDepth {
+		return o, false
+	}
+	n := len(o)
+	if lm.MaxElements > 0 && n > lm.MaxElements {
+		n = lm.MaxElements
+	}
+	changed := n < len(o)
+	lo := make(Object, n, n+1)
+	for i := 0; i < n; i++ {
+		v, ok := lm.value(o[i].Value, depth+1)
+		lo[i] = Field{Key: o[i].Key, Value: v}
+		changed = changed || ok
+	}
+	if !changed {
+		return o, false
+	}
+	if n < len(o) {
+		lo = append(lo, Field{Key: TruncatedKey, Value: TruncatedMarker(len(o)-n, "elements")})
+	}
+	return lo, true
+}
+
+// slice is converted to []interface{} when it is changed,
+// dropped elements are replaced with marker
+func (lm Limits) slice(rv reflect.Value, depth int) (interface{}, bool) {
+	n := rv.Len()
+	if lm.MaxElements > 0 && n > lm.MaxElements {
+		n = lm.MaxElements
+	}
+	changed := n < rv.Len()
+	if !changed && isScalarKind(rv.Type().Elem().Kind()) {
+		return rv.Interface(), false
+	}
+	ls := make([]interface{}, n, n+1)
+	for i := 0; i < n; i++ {
+		v, ok := lm.value(rv.Index(i).Interface(), depth+1)
+		ls[i] = v
+		changed = changed || ok
+	}
+	if !changed {
+		return rv.Interface(), false
+	}
+	if n < rv.Len() {
+		ls = append(ls, TruncatedMarker(rv.Len()-n, "elements"))
+	}
+	return ls, true
+}
+
+// map is converted to map[string]interface{} when it is changed,
+// entries with the smallest keys are kept
+func (lm Limits) mapValue(rv reflect.Value, depth int) (interface{}, bool) {
+	keys := rv.MapKeys()
+	n := len(keys)
+	if lm.MaxElements > 0 && n > lm.MaxElements {
+		n = lm.MaxElements
+	}
+	changed := n < len(keys)
+	if !changed && isScalarKind(rv.Type().Elem().Kind()) {
+		return rv.Interface(), false
+	}
+	skeys := make([]string, len(keys))
+	for i, k := range keys {
+		skeys[i] = fmt.Sprint(k.Interface())
+	}
+	if changed {
+		sort.Sort(mapKeys{skeys, keys})
+	}
+	lmap := make(map[string]interface{}, n+1)
+	for i := 0; i < n; i++ {
+		v, ok := lm.value(rv.MapIndex(keys[i]).Interface(), depth+1)
+		lmap[skeys[i]] = v
+		changed = changed || ok
+	}
+	if !changed {
+		return rv.Interface(), false
+	}
+	if n < len(keys) {
+		lmap[TruncatedKey] = TruncatedMarker(len(keys)-n, "elements")
+	}
+	return lmap, true
+}
+
+// mapKeys sorts string form of keys together with the keys
+type mapKeys struct {
+	str  []string
+	keys []reflect.Value
+}
+
+func (mk mapKeys) Len() int           { return len(mk.str) }
+func (mk mapKeys) Less(i, j int) bool { return mk.str[i] < mk.str[j] }
+func (mk mapKeys) Swap(i, j int) {
+	mk.str[i], mk.str[j] = mk.str[j], mk.str[i]
+	mk.keys[i], mk.keys[j] = mk.keys[j], mk.keys[i]
+}
+
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// truncatedError keeps original error (e.g. for errors.Is) with truncated message
+type truncatedError struct {
+	error
+	msg string
+}
+
+func (te *truncatedError) Error() string {
+	return te.msg
+}
+
+func (te *truncatedError) Unwrap() error {
+	return te.error
+}
+
+// errorType return type name of err, truncated error reports type of the original
+func errorType(err error) string {
+	if te, ok := err.(*truncatedError); ok {
+		err = te.error
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+type limitEncoder struct {
+	enc      Encoder
+	maxBytes int
+}
+
+// LimitEncoder return encoder which keeps each encoded entry within maxBytes.
+// The largest field values are replaced with truncation marker first,
+// then the last fields are dropped and replaced with single TruncatedKey field,
+// then message is truncated. Finally the output of encoder which does not write
+// JSON (see IsJSONEncoder) is cut; JSON output is never cut, so it may stay above
+// maxBytes. Entry within the limit is encoded once; oversized entry is encoded
+// again after each replacement, and size of its values is computed once using
+// AsString (i.e. non-string values are formatted).
+// Non positive maxBytes returns enc.
+func LimitEncoder(enc Encoder, maxBytes int) Encoder {
+	if maxBytes <= 0 {
+		return enc
+	}
+	return &limitEncoder{enc: enc, maxBytes: maxBytes}
+}
+
+// ContentType of wrapped encoder
+func (le *limitEncoder) ContentType() string {
+	if ct, ok := le.enc.(ContentTyper); ok {
+		return ct.ContentType()
+	}
+	return ""
+}
+
+// Encode entry, the entry itself is not modified
+func (le *limitEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	start := buf.Len()
+	if err := le.enc.Encode(buf, e); err != nil {
+		return err
+	}
+	size := buf.Len() - start
+	if size <= le.maxBytes {
+		return nil
+	}
+
+	ce := *e
+	ce.Fields = append(Fields(nil), e.Fields...)
+	encode := func() error {
+		buf.Truncate(start)
+		err := le.enc.Encode(buf, &ce)
+		size = buf.Len() - start
+		return err
+	}
+
+	// replace the largest values while it shrinks the entry
+	sizes := make([]int, len(ce.Fields))
+	for i, f := range ce.Fields {
+		sizes[i] = valueSize(f.Value)
+	}
+	for size > le.maxBytes {
+		idx := 0
+		for i := range sizes {
+			if sizes[i] > sizes[idx] {
+				idx = i
+			}
+		}
+		if len(sizes) == 0 || sizes[idx] <= len(TruncatedMarker(sizes[idx], "bytes")) {
+			break
+		}
+		ce.Fields[idx].Value = TruncatedMarker(sizes[idx], "bytes")
+		sizes[idx] = 0
+		if err := encode(); err != nil {
+			return err
+		}
+	}
+
+	// then drop the last fields
+	fields := ce.Fields
+	for n := len(fields) - 1; size > le.maxBytes && n >= 0; n-- {
+		ce.Fields = append(fields[:n:n], Field{Key: TruncatedKey, Value: TruncatedMarker(len(fields)-n, "fields")})
+		if err := encode(); err != nil {
+			return err
+		}
+	}
+
+	// then message
+	msg := ce.Message
+	keep := len(msg)
+	for size > le.maxBytes && keep > 0 {
+		over := size - le.maxBytes
+		if keep == len(msg) {
+			// marker is added by the first cut
+			over += len(TruncatedMarker(len(msg), "bytes"))
+		}
+		keep -= over
+		if keep < 0 {
+			keep = 0
+		}
+		ce.Message = truncate(msg, keep)
+		if err := encode(); err != nil {
+			return err
+		}
+	}
+
+	// finally cut the output, keeping line terminator, JSON is left valid
+	if size > le.maxBytes && !IsJSONEncoder(le.enc) {
+		b := buf.Bytes()[start:]
+		nl := b[len(b)-1] == '\n'
+		n := le.maxBytes
+		if nl {
+			n--
+		}
+		for n > 0 && !utf8.RuneStart(b[n]) {
+			n--
+		}
+		buf.Truncate(start + n)
+		if nl {
+			buf.WriteByte('\n')
+		}
+	}
+	return nil
+}
+
+// valueSize return length of value written as string
+func valueSize(val interface{}) int {
+	switch v := val.(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case error:
+		return len(v.Error())
+	}
+	str, _ := AsString(val)
+	return len(str)
+}
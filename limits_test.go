@@ -0,0 +1,187 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"unlimited", "hello", 0, "hello"},
+		{"negative", "hello", -1, "hello"},
+		{"within limit", "hello", 5, "hello"},
+		{"ascii", "hello world", 5, "hello…(truncated 6 bytes)"},
+		{"rune boundary", "héllo", 2, "h…(truncated 5 bytes)"},
+		{"cut before first rune", "世界", 2, "…(truncated 6 bytes)"},
+		{"after first rune", "世界", 4, "世…(truncated 3 bytes)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateString(tt.s, tt.n)
+			if got != tt.want {
+				t.Errorf("TruncateString(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("invalid UTF-8 %q", got)
+			}
+		})
+	}
+}
+
+func TestLimitsApplyFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		in     []interface{}
+		want   Fields
+	}{
+		{"unlimited", Limits{}, []interface{}{"a", "long value"}, Fields{{"a", "long value"}}},
+		{"max fields", Limits{MaxFields: 1}, []interface{}{"a", 1, "b", 2, "c", 3},
+			Fields{{"a", 1}, {TruncatedKey, "…(truncated 2 fields)"}}},
+		{"string value", Limits{MaxValue: 3}, []interface{}{"a", "abcdef", "n", 12345},
+			Fields{{"a", "abc…(truncated 3 bytes)"}, {"n", 12345}}},
+		{"bytes value", Limits{MaxValue: 3}, []interface{}{"a", []byte("abcdef")},
+			Fields{{"a", "abc…(truncated 3 bytes)"}}},
+		{"slice elements", Limits{MaxElements: 2}, []interface{}{"a", []int{1, 2, 3}},
+			Fields{{"a", []interface{}{1, 2, "…(truncated 1 elements)"}}}},
+		{"nested strings", Limits{MaxValue: 2}, []interface{}{"a", []string{"abc", "d"}},
+			Fields{{"a", []interface{}{"ab…(truncated 1 bytes)", "d"}}}},
+		{"map keeps smallest keys", Limits{MaxElements: 1}, []interface{}{"a", map[string]int{"y": 1, "x": 2}},
+			Fields{{"a", map[string]interface{}{"x": 2, TruncatedKey: "…(truncated 1 elements)"}}}},
+		{"group elements", Limits{MaxElements: 1}, []interface{}{Group("g", "x", 1, "y", 2)},
+			Fields{{"g", Object{{"x", 1}, {TruncatedKey, "…(truncated 1 elements)"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fields(tt.limits.ApplyFields(ResolveFields(ToFields(tt.in))))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsTruncatedError(t *testing.T) {
+	err := errors.New("connection refused")
+	fields := Limits{MaxValue: 4}.ApplyFields([]Field{{"err", err}})
+	got, ok := fields[0].Value.(error)
+	if !ok {
+		t.Fatalf("value %T is not error", fields[0].Value)
+	}
+	if got.Error() != "conn…(truncated 14 bytes)" || !errors.Is(got, err) {
+		t.Errorf("truncated error %q does not wrap original", got)
+	}
+	if errorType(got) != "*errors.errorString" {
+		t.Errorf("error type %s", errorType(got))
+	}
+}
+
+// countingEncoder counts calls of wrapped encoder
+type countingEncoder struct {
+	Encoder
+	calls int
+}
+
+func (ce *countingEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	ce.calls++
+	return ce.Encoder.Encode(buf, e)
+}
+
+func TestLimitEncoder(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	tests := []struct {
+		name     string
+		max      int
+		msg      string
+		fields   []interface{}
+		want     string
+		encoding int
+	}{
+		{"within limit", 100, "hello", []interface{}{"k", "v"}, "level=info msg=hello k=v\n", 1},
+		{"largest value replaced", 60, "hello", []interface{}{"a", long, "b", "short"},
+			`level=info msg=hello a="…(truncated 100 bytes)" b=short` + "\n", 2},
+		{"message truncated", 50, long, nil,
+			`level=info msg="xxxxxxxxx…(truncated 91 bytes)"` + "\n", 3},
+		{"message replaced then cut", 40, long, nil,
+			`level=info msg="…(truncated 100 bytes` + "\n", 2},
+		{"last fields dropped", 80, "hello", []interface{}{"a", "value-1", "b", "value-2", "c", "value-3", "d", "value-4",
+			"e", "value-5", "f", "value-6", "g", "value-7", "h", "value-8"},
+			`level=info msg=hello a=value-1 b=value-2 _truncated="…(truncated 6 fields)"` + "\n", 7},
+		{"final cut at rune boundary", 18, strings.Repeat("世界", 10), nil, `level=info msg="` + "\n", 2},
+		{"final cut keeps newline", 12, "hello", []interface{}{"k", "v"}, "level=info \n", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ce := &countingEncoder{Encoder: &LogfmtEncoder{TimestampFormat: TimestampNone}}
+			enc := LimitEncoder(ce, tt.max)
+			e := NewEntry(InfoLevel, tt.msg, tt.fields)
+			orig := *e
+			orig.Fields = append(Fields(nil), e.Fields...)
+
+			var buf bytes.Buffer
+			buf.WriteString("prefix ")
+			if err := enc.Encode(&buf, e); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.TrimPrefix(buf.String(), "prefix ")
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if len(got) > tt.max || !utf8.ValidString(got) {
+				t.Errorf("output %q exceeds %d bytes or is invalid UTF-8", got, tt.max)
+			}
+			if ce.calls != tt.encoding {
+				t.Errorf("encoded %d times, want %d", ce.calls, tt.encoding)
+			}
+			if !reflect.DeepEqual(e.Fields, orig.Fields) || e.Message != orig.Message {
+				t.Error("entry modified")
+			}
+		})
+	}
+
+	plain := &LogfmtEncoder{}
+	if enc := LimitEncoder(plain, 0); enc != Encoder(plain) {
+		t.Errorf("LimitEncoder without limit = %T, want wrapped encoder", enc)
+	}
+}
+
+func TestLimitEncoderJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		max    int
+		msg    string
+		fields []interface{}
+		want   string
+	}{
+		{"last fields dropped", 100, "hello", []interface{}{"a", "value-1", "b", "value-2", "c", "value-3", "d", "value-4",
+			"e", "value-5", "f", "value-6", "g", "value-7", "h", "value-8"},
+			`{"level":"info","msg":"hello","a":"value-1","b":"value-2","_truncated":"…(truncated 6 fields)"}` + "\n"},
+		{"not cut above limit", 20, "hello", []interface{}{"k", "v"},
+			`{"level":"info","msg":"…(truncated 5 bytes)","_truncated":"…(truncated 1 fields)"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := LimitEncoder(&JSONEncoder{TimestampFormat: TimestampNone}, tt.max)
+			var buf bytes.Buffer
+			if err := enc.Encode(&buf, NewEntry(InfoLevel, tt.msg, tt.fields)); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Errorf("invalid JSON %q", buf.String())
+			}
+		})
+	}
+}
@@ -2,8 +2,8 @@ package slog
 
 import (
 	"errors"
+	"fmt"
 	"io"
-	"os"
 	"sync"
 )
 
@@ -13,24 +13,8 @@ var (
 	constructors   = make(map[string]Constructor)
 )
 
-// Package logger instance
-var (
-	pkgLoggerMu sync.Mutex
-	discardL    *discardLogger = &discardLogger{LevelLoggerBase: LevelLoggerBase{level: AllLevel}}
-)
-
-// Unknown field name and default logger
-var (
-	UnknownFieldName        = "@logfield"
-	DefaultLogger    Logger = discardL
-)
-
-func init() {
-	// Switch to STD Logger
-	if lgr, err := NewStdLogger(os.Stdout, TraceLevel, nil); err == nil {
-		DefaultLogger = lgr
-	}
-}
+// Unknown field name
+var UnknownFieldName = "@logfield"
 
 // Register logger constructor
 func Register(name string, constructor Constructor) {
@@ -58,7 +42,7 @@ func ConstructorFor(name string) (Constructor, bool) {
 }
 
 // Global discard logger
-var Discard Logger = &discardLogger{LevelLoggerBase: LevelLoggerBase{level: AllLevel}}
+var Discard Logger = newDiscardLogger(AllLevel, MaskMode)
 
 // Constructor for reader creator
 type Constructor interface {
@@ -108,6 +92,16 @@ type Logger interface {
 	Panicw(msg string, keyVals ...interface{})
 }
 
+// LevelLogger is Logger with primitive operations, level is given as parameter.
+// These methods do not exit or panic for FatalLevel and PanicLevel.
+// Loggers of this package implement it, use Leveled to get it from any Logger.
+type LevelLogger interface {
+	Logger
+	Log(lv Level, args ...interface{})
+	Logf(lv Level, format string, args ...interface{})
+	Logw(lv Level, msg string, keyVals ...interface{})
+}
+
 // New create new logger with given name
 func New(name string, w io.Writer, l Level) (Logger, error) {
 	// get constructor
@@ -131,6 +125,13 @@ func NewWithOptions(name string, w io.Writer, l Level, op Options) (Logger, erro
 		return nil, errors.New("unknown logger: " + name)
 	}
 
+	// validate options if constructor declares its schema
+	if sp, ok := c.(SchemaProvider); ok {
+		if err := sp.Schema().Validate(op); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
 	lgr, err := c.NewWithOptions(w, l, op)
 	if err != nil {
 		return nil, err
@@ -147,122 +148,146 @@ func MustUse(name string, w io.Writer, l Level) {
 
 // Use specific logger
 func Use(name string, w io.Writer, l Level) error {
-	pkgLoggerMu.Lock()
-	defer pkgLoggerMu.Unlock()
-
 	lgr, err := New(name, w, l)
 	if err != nil {
 		return err
 	}
-	DefaultLogger = lgr
+	SetDefault(lgr)
+
+	return nil
+}
+
+// MustUseWithOptions use specific logger with options
+func MustUseWithOptions(name string, w io.Writer, l Level, op Options) {
+	if err := UseWithOptions(name, w, l, op); err != nil {
+		panic(err)
+	}
+}
+
+// UseWithOptions use specific logger with options
+func UseWithOptions(name string, w io.Writer, l Level, op Options) error {
+	lgr, err := NewWithOptions(name, w, l, op)
+	if err != nil {
+		return err
+	}
+	SetDefault(lgr)
 
 	return nil
 }
 
-// may be unsafe?
 func HasLevel(lv Level) bool {
-	return DefaultLogger.HasLevel(lv)
+	return Default().HasLevel(lv)
 }
 func SetLevel(lv Level) {
-	DefaultLogger.SetLevel(lv)
+	Default().SetLevel(lv)
+}
+
+func Log(lv Level, args ...interface{}) {
+	Leveled(Default()).Log(lv, args...)
+}
+func Logf(lv Level, format string, args ...interface{}) {
+	Leveled(Default()).Logf(lv, format, args...)
+}
+func Logw(lv Level, msg string, keyVals ...interface{}) {
+	Leveled(Default()).Logw(lv, msg, keyVals...)
 }
 
 func Trace(args ...interface{}) {
-	DefaultLogger.Trace(args...)
+	Default().Trace(args...)
 }
 func Debug(args ...interface{}) {
-	DefaultLogger.Debug(args...)
+	Default().Debug(args...)
 }
 func Print(args ...interface{}) {
-	DefaultLogger.Print(args...)
+	Default().Print(args...)
 }
 func Info(args ...interface{}) {
-	DefaultLogger.Info(args...)
+	Default().Info(args...)
 }
 func Warn(args ...interface{}) {
-	DefaultLogger.Warn(args...)
+	Default().Warn(args...)
 }
 func Error(args ...interface{}) {
-	DefaultLogger.Error(args...)
+	Default().Error(args...)
 }
 func Fatal(args ...interface{}) {
-	DefaultLogger.Fatal(args...)
+	Default().Fatal(args...)
 }
 func Panic(args ...interface{}) {
-	DefaultLogger.Panic(args...)
+	Default().Panic(args...)
 }
 
 func Traceln(args ...interface{}) {
-	DefaultLogger.Traceln(args...)
+	Default().Traceln(args...)
 }
 func Debugln(args ...interface{}) {
-	DefaultLogger.Debugln(args...)
+	Default().Debugln(args...)
 }
 func Println(args ...interface{}) {
-	DefaultLogger.Println(args...)
+	Default().Println(args...)
 }
 func Infoln(args ...interface{}) {
-	DefaultLogger.Infoln(args...)
+	Default().Infoln(args...)
 }
 func Warnln(args ...interface{}) {
-	DefaultLogger.Warnln(args...)
+	Default().Warnln(args...)
 }
 func Errorln(args ...interface{}) {
-	DefaultLogger.Errorln(args...)
+	Default().Errorln(args...)
 }
 func Fatalln(args ...interface{}) {
-	DefaultLogger.Fatalln(args...)
+	Default().Fatalln(args...)
 }
 func Panicln(args ...interface{}) {
-	DefaultLogger.Panicln(args...)
+	Default().Panicln(args...)
 }
 
 func Tracef(format string, args ...interface{}) {
-	DefaultLogger.Tracef(format, args...)
+	Default().Tracef(format, args...)
 }
 func Debugf(format string, args ...interface{}) {
-	DefaultLogger.Debugf(format, args...)
+	Default().Debugf(format, args...)
 }
 func Printf(format string, args ...interface{}) {
-	DefaultLogger.Printf(format, args...)
+	Default().Printf(format, args...)
 }
 func Infof(format string, args ...interface{}) {
-	DefaultLogger.Infof(format, args...)
+	Default().Infof(format, args...)
 }
 func Warnf(format string, args ...interface{}) {
-	DefaultLogger.Warnf(format, args...)
+	Default().Warnf(format, args...)
 }
 func Errorf(format string, args ...interface{}) {
-	DefaultLogger.Errorf(format, args...)
+	Default().Errorf(format, args...)
 }
 func Fatalf(format string, args ...interface{}) {
-	DefaultLogger.Fatalf(format, args...)
+	Default().Fatalf(format, args...)
 }
 func Panicf(format string, args ...interface{}) {
-	DefaultLogger.Panicf(format, args...)
+	Default().Panicf(format, args...)
 }
 
 func Tracew(msg string, keyVals ...interface{}) {
-	DefaultLogger.Tracew(msg, keyVals...)
+	Default().Tracew(msg, keyVals...)
 }
 func Debugw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Debugw(msg, keyVals...)
+	Default().Debugw(msg, keyVals...)
 }
 func Printw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Printw(msg, keyVals...)
+	Default().Printw(msg, keyVals...)
 }
 func Infow(msg string, keyVals ...interface{}) {
-	DefaultLogger.Infow(msg, keyVals...)
+	Default().Infow(msg, keyVals...)
 }
 func Warnw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Warnw(msg, keyVals...)
+	Default().Warnw(msg, keyVals...)
 }
 func Errorw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Errorw(msg, keyVals...)
+	Default().Errorw(msg, keyVals...)
 }
 func Fatalw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Fatalw(msg, keyVals...)
+	Default().Fatalw(msg, keyVals...)
 }
 func Panicw(msg string, keyVals ...interface{}) {
-	DefaultLogger.Panicw(msg, keyVals...)
+	Default().Panicw(msg, keyVals...)
 }
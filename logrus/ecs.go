@@ -0,0 +1,62 @@
+package logrus
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ipsusila/slog"
+	log "github.com/sirupsen/logrus"
+)
+
+// map logrus level to slog level
+var slogLevelMapper = map[log.Level]slog.Level{
+	log.PanicLevel: slog.PanicLevel,
+	log.FatalLevel: slog.FatalLevel,
+	log.ErrorLevel: slog.ErrorLevel,
+	log.WarnLevel:  slog.WarnLevel,
+	log.InfoLevel:  slog.InfoLevel,
+	log.DebugLevel: slog.DebugLevel,
+	log.TraceLevel: slog.TraceLevel,
+}
+
+// ECSFormatter formats logrus entry as Elastic Common Schema JSON
+// using slog.ECSEncoder. Data fields are sorted by key.
+type ECSFormatter struct {
+	// LoggerName written as log.logger
+	LoggerName string
+}
+
+// Format logrus entry
+func (f *ECSFormatter) Format(le *log.Entry) ([]byte, error) {
+	keys := make([]string, 0, len(le.Data))
+	for k := range le.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]slog.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, slog.Field{Key: k, Value: le.Data[k]})
+	}
+
+	e := &slog.Entry{
+		Time:    le.Time,
+		Level:   slogLevelMapper[le.Level],
+		Message: le.Message,
+		Fields:  fields,
+		Logger:  f.LoggerName,
+		Context: le.Context,
+	}
+	if le.HasCaller() {
+		e.Caller = &slog.Caller{
+			Function: le.Caller.Function,
+			File:     le.Caller.File,
+			Line:     le.Caller.Line,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (&slog.ECSEncoder{}).Encode(&buf, e); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,114 @@
+package logrus
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ipsusila/slog"
+	log "github.com/sirupsen/logrus"
+)
+
+// LimitFormatter keeps each formatted entry within MaxBytes.
+// The largest data values are replaced with truncation marker first,
+// then data keys are dropped in reverse order and replaced with single
+// slog.TruncatedKey field, then message is truncated. Finally output of
+// formatter other than JSON and ECS is cut; JSON is left valid above MaxBytes.
+type LimitFormatter struct {
+	log.Formatter
+	MaxBytes int
+}
+
+// Format logrus entry, the entry itself is not modified
+func (f *LimitFormatter) Format(le *log.Entry) ([]byte, error) {
+	b, err := f.Formatter.Format(le)
+	if err != nil || f.MaxBytes <= 0 || len(b) <= f.MaxBytes {
+		return b, err
+	}
+
+	ce := *le
+	ce.Buffer = nil
+	ce.Data = make(log.Fields, len(le.Data))
+	sizes := make(map[string]int, len(le.Data))
+	for k, v := range le.Data {
+		ce.Data[k] = v
+		str, _ := slog.AsString(v)
+		sizes[k] = len(str)
+	}
+	format := func() error {
+		b, err = f.Formatter.Format(&ce)
+		return err
+	}
+
+	// replace the largest values while it shrinks the entry
+	for len(b) > f.MaxBytes {
+		key, size := "", 0
+		for k, n := range sizes {
+			if n > size || (n == size && k < key) {
+				key, size = k, n
+			}
+		}
+		if size <= len(slog.TruncatedMarker(size, "bytes")) {
+			break
+		}
+		ce.Data[key] = slog.TruncatedMarker(size, "bytes")
+		delete(sizes, key)
+		if err := format(); err != nil {
+			return nil, err
+		}
+	}
+
+	// then drop the last keys
+	keys := make([]string, 0, len(ce.Data))
+	for k := range ce.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for n := len(keys) - 1; len(b) > f.MaxBytes && n >= 0; n-- {
+		delete(ce.Data, keys[n])
+		ce.Data[slog.TruncatedKey] = slog.TruncatedMarker(len(keys)-n, "fields")
+		if err := format(); err != nil {
+			return nil, err
+		}
+	}
+
+	// then message
+	msg := ce.Message
+	keep := len(msg)
+	for len(b) > f.MaxBytes && keep > 0 {
+		keep -= len(b) - f.MaxBytes + len(slog.TruncatedMarker(len(msg), "bytes"))
+		if keep <= 0 {
+			keep = 0
+			ce.Message = slog.TruncatedMarker(len(msg), "bytes")
+		} else {
+			ce.Message = slog.TruncateString(msg, keep)
+		}
+		if err := format(); err != nil {
+			return nil, err
+		}
+	}
+
+	// finally cut the output, keeping line terminator, JSON is left valid
+	if len(b) > f.MaxBytes && !isJSONFormatter(f.Formatter) {
+		nl := b[len(b)-1] == '\n'
+		n := f.MaxBytes
+		if nl {
+			n--
+		}
+		for n > 0 && !utf8.RuneStart(b[n]) {
+			n--
+		}
+		b = b[:n]
+		if nl {
+			b = append(b, '\n')
+		}
+	}
+	return b, nil
+}
+
+func isJSONFormatter(f log.Formatter) bool {
+	switch f.(type) {
+	case *log.JSONFormatter, *ECSFormatter:
+		return true
+	}
+	return false
+}
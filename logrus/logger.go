@@ -3,6 +3,7 @@ package logrus
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/ipsusila/slog"
@@ -17,8 +18,10 @@ type logrusConstructor struct{}
 const (
 	defaultTimestampFormat         = "2006/01/02 15:04:05 MST"
 	fieldFormatter                 = "formatter"
+	fieldName                      = "name"
 	fieldTimestampFormat           = "timestampFormat"
-	fieldDisableTimestamp          = "disbleTimestamp"
+	fieldDisableTimestamp          = "disableTimestamp"
+	fieldDisbleTimestamp           = "disbleTimestamp" // deprecated misspelled alias
 	fieldReportCaller              = "reportCaller"
 	fieldFulltimeStamp             = "fullTimestamp"
 	fieldMapper                    = "fieldMap"
@@ -36,37 +39,105 @@ const (
 	fieldQuoteEmptyFields          = "quoteEmptyFields"
 )
 
-// logger without output, except for panic
+// options supported by logrus logger
+var logrusSchema = slog.Schema{
+	{Name: fieldFormatter, Type: slog.StringOption, Default: "text", Description: "logrus formatter, text, json or ecs",
+		Values: []string{"text", "json", "ecs"}},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name written as log.logger (ecs)"},
+	{Name: fieldTimestampFormat, Type: slog.StringOption, Default: defaultTimestampFormat, Description: "timestamp layout format"},
+	{Name: fieldDisableTimestamp, Type: slog.BoolOption, Default: false, Description: "disable timestamp in log"},
+	{Name: fieldDisbleTimestamp, Type: slog.BoolOption, Description: "deprecated, use disableTimestamp"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add calling method as a field"},
+	{Name: fieldFulltimeStamp, Type: slog.BoolOption, Default: true, Description: "log full timestamp instead of elapsed time (text)"},
+	{Name: fieldMapper, Type: slog.OptionsOption, Description: "customize default key names"},
+	{Name: fieldDataKey, Type: slog.StringOption, Default: "", Description: "data key (json)"},
+	{Name: fieldPrettyPrint, Type: slog.BoolOption, Default: false, Description: "pretty print output (json)"},
+	{Name: fieldDisableHTMLEscape, Type: slog.BoolOption, Default: false, Description: "disable HTML escape (json)"},
+	{Name: fieldForceColors, Type: slog.BoolOption, Default: false, Description: "force colors (text)"},
+	{Name: fieldDisableColors, Type: slog.BoolOption, Default: false, Description: "disable colors (text)"},
+	{Name: fieldForceQuote, Type: slog.BoolOption, Default: false, Description: "force quoting of all values (text)"},
+	{Name: fieldDisableQuote, Type: slog.BoolOption, Default: false, Description: "disable quoting of values (text)"},
+	{Name: fieldEnvironmentOverrideColors, Type: slog.BoolOption, Default: false, Description: "override colors based on environment (text)"},
+	{Name: fieldDisableSorting, Type: slog.BoolOption, Default: false, Description: "disable key sorting (text)"},
+	{Name: fieldDisableLevelTruncation, Type: slog.BoolOption, Default: false, Description: "disable level string truncation (text)"},
+	{Name: fieldPadLevelText, Type: slog.BoolOption, Default: false, Description: "pad level string (text)"},
+	{Name: fieldQuoteEmptyFields, Type: slog.BoolOption, Default: false, Description: "quote empty fields (text)"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+	slog.OptionMaxMessageLength,
+	slog.OptionMaxValueLength,
+	slog.OptionMaxFields,
+	slog.OptionMaxElements,
+	slog.OptionMaxEntryBytes,
+}
+
+// map slog level to logrus level
+var levelMapper = map[slog.Level]log.Level{
+	slog.PanicLevel: log.PanicLevel,
+	slog.FatalLevel: log.FatalLevel,
+	slog.ErrorLevel: log.ErrorLevel,
+	slog.WarnLevel:  log.WarnLevel,
+	slog.InfoLevel:  log.InfoLevel,
+	slog.DebugLevel: log.DebugLevel,
+	slog.TraceLevel: log.TraceLevel,
+}
+
+// logger which writes using logrus.
+// Logrus level is set to the least severe enabled level,
+// other levels are filtered by the adapter (e.g. in mask mode).
 type logrusLogger struct {
-	log.Logger
-	level slog.Level
+	slog.LevelLoggerBase
+	slog.LoggerBase
+	lr       *log.Logger
+	policy   slog.FieldPolicy
+	redactor slog.Redactor
+	limits   slog.Limits
 }
 
 func init() {
 	slog.Register(Name, &logrusConstructor{})
 }
 
+// toLogrusLevel return logrus level of the least severe level in the flags
 func toLogrusLevel(l slog.Level) (log.Level, bool) {
-	all := slog.Levels()
-	maxIdx := -1
-	for idx, lv := range all {
+	var least slog.Level
+	for _, lv := range slog.Levels() {
 		if l.Has(lv) {
-			maxIdx = idx
+			least = lv
 		}
 	}
-	if maxIdx >= 0 {
-		ui32 := uint32(maxIdx) + uint32(log.PanicLevel)
-		return log.Level(ui32), true
+	if least == 0 {
+		return 0, false
 	}
-	return 0, false
+	return levelMapper[least.Builtin()], true
 }
 
-// New creates discard logger
+// New creates logrus logger
 func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := logrusSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
 	ll, ok := toLogrusLevel(l)
 	if !ok {
 		return nil, fmt.Errorf("unknown logger level: %v", l)
 	}
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
 
 	// customize standard fields
 	var fieldMap = log.FieldMap{
@@ -95,11 +166,13 @@ func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
 			fieldMap[key] = fm.GetString(string(key), val)
 		}
 
-		disableTimestamp := op.GetBool(fieldDisableTimestamp, false)
+		disableTimestamp := op.GetBool(fieldDisableTimestamp, op.GetBool(fieldDisbleTimestamp, false))
 
 		// formatter options
-		txtF := op.GetString(fieldFormatter, "text")
+		txtF := strings.ToLower(op.GetString(fieldFormatter, "text"))
 		switch txtF {
+		case "ecs":
+			formatter = &ECSFormatter{LoggerName: op.GetString(fieldName, "")}
 		case "json":
 			formatter = &log.JSONFormatter{
 				TimestampFormat:   op.GetString(fieldTimestampFormat, time.RFC3339),
@@ -129,65 +202,99 @@ func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
 	}
 	// end options
 
-	lr := log.Logger{
-		Out:          w,
-		Formatter:    formatter,
-		Level:        ll,
-		ReportCaller: reportCaller,
+	lr := log.New()
+	lr.Out = w
+	lr.Formatter = formatter
+	if n := op.GetInt(slog.OptionMaxEntryBytes.Name, 0); n > 0 {
+		lr.Formatter = &LimitFormatter{Formatter: formatter, MaxBytes: n}
 	}
-	lg := logrusLogger{
-		Logger: lr,
-		level:  l,
-	}
-	return &lg, nil
+	lr.Level = ll
+	lr.ReportCaller = reportCaller
+	lg := &logrusLogger{lr: lr, policy: policy, redactor: redactor, limits: op.GetLimits()}
+	lg.LoggerBase = slog.NewLoggerBase(lg)
+	lg.LevelLoggerBase.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
 }
 
 func (c *logrusConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
-	return New(w, l, nil)
+	return newLogger(w, l, nil)
 }
 func (c *logrusConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
-	return New(w, l, op)
+	return newLogger(w, l, op)
 }
 
-func (l *logrusLogger) HasLevel(lv slog.Level) bool {
-	return l.level.Has(lv)
+// Schema return options supported by logrus logger
+func (c *logrusConstructor) Schema() slog.Schema {
+	return logrusSchema
 }
+
 func (l *logrusLogger) SetLevel(lv slog.Level) {
-	if ll, ok := toLogrusLevel(lv); ok {
-		l.level = lv
-		l.Logger.SetLevel(ll)
+	if _, ok := toLogrusLevel(lv); ok {
+		l.LevelLoggerBase.SetLevel(lv)
+		l.syncLevel()
 	}
 }
-
-func (l *logrusLogger) Tracew(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Trace(msg)
-}
-func (l *logrusLogger) Debugw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Debug(msg)
+func (l *logrusLogger) SetLevelMode(m slog.LevelMode) {
+	l.LevelLoggerBase.SetLevelMode(m)
+	l.syncLevel()
 }
-func (l *logrusLogger) Printw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Print(msg)
+
+// set logrus level to the least severe enabled level
+func (l *logrusLogger) syncLevel() {
+	if ll, ok := toLogrusLevel(l.Level()); ok {
+		l.lr.SetLevel(ll)
+	}
 }
-func (l *logrusLogger) Infow(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Info(msg)
+
+// Exit using logrus exit function, called by Fatal methods
+func (l *logrusLogger) Exit(code int) {
+	l.lr.Exit(code)
 }
-func (l *logrusLogger) Warnw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Warn(msg)
+
+// entry writes using nearest logrus level, user defined level is mapped by severity.
+// Fields are redacted and limited before they are passed to logrus.
+func (l *logrusLogger) entry(lv slog.Level, msg string, fields []slog.Field) {
+	if l.redactor != nil {
+		e := &slog.Entry{Time: slog.Now(), Level: lv, Message: msg, Fields: fields}
+		l.redactor.Redact(e)
+		msg, fields = e.Message, e.Fields
+	}
+	fields = l.limits.ApplyFields(fields)
+	data := make(log.Fields, len(fields))
+	for _, f := range fields {
+		data[f.Key] = f.Value
+	}
+
+	ll := levelMapper[lv.Builtin()]
+	if ll == log.PanicLevel {
+		// logrus always panics in panic level, Log only writes the entry
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(*log.Entry); !ok {
+					panic(r)
+				}
+			}
+		}()
+	}
+	l.lr.WithFields(data).Log(ll, slog.TruncateString(msg, l.limits.MaxMessage))
 }
-func (l *logrusLogger) Errorw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Error(msg)
+
+func (l *logrusLogger) Log(lv slog.Level, args ...interface{}) {
+	if l.HasLevel(lv) {
+		l.entry(lv, fmt.Sprint(args...), nil)
+	}
 }
-func (l *logrusLogger) Fatalw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Fatal(msg)
+func (l *logrusLogger) Logf(lv slog.Level, format string, args ...interface{}) {
+	if l.HasLevel(lv) {
+		l.entry(lv, fmt.Sprintf(format, args...), nil)
+	}
 }
-func (l *logrusLogger) Panicw(msg string, keyVals ...interface{}) {
-	fields := slog.FieldsToMap(keyVals)
-	l.WithFields(log.Fields(fields)).Panic(msg)
+func (l *logrusLogger) Logw(lv slog.Level, msg string, keyVals ...interface{}) {
+	if l.HasLevel(lv) {
+		// nested objects (e.g. group) are flattened into dotted keys, logrus keeps
+		// fields in a map so formatters write them sorted by key
+		l.entry(lv, msg, l.policy.Apply(slog.FlattenFields(slog.ResolveFields(slog.ToFields(keyVals)))))
+	}
 }
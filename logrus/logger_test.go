@@ -0,0 +1,153 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+func TestDeprecatedDisableTimestamp(t *testing.T) {
+	for _, key := range []string{"disableTimestamp", "disbleTimestamp"} {
+		t.Run(key, func(t *testing.T) {
+			var buf bytes.Buffer
+			l, err := New(&buf, slog.InfoLevel, slog.Options{key: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Info("hello")
+			if strings.Contains(buf.String(), "@time") {
+				t.Errorf("timestamp written: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestFieldOrderAndDuplicates(t *testing.T) {
+	tests := []struct {
+		name string
+		op   slog.Options
+		want string
+	}{
+		{"json sorted", slog.Options{"formatter": "json"}, `"a":2,"b":3,"c":1`},
+		{"text sorted", slog.Options{"disableTimestamp": true}, `a=2 b=3 c=1`},
+		{"first wins", slog.Options{"formatter": "json", "duplicateKeys": "First"}, `"a":2,"b":1,"c":1`},
+		{"suffix", slog.Options{"formatter": "json", "duplicateKeys": "suffix"}, `"a":2,"b":1,"b_2":3,"c":1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l, err := New(&buf, slog.InfoLevel, tt.op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Infow("msg", "c", 1, "b", 1, "a", 2, "b", 3)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("got %s, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := New(&bytes.Buffer{}, slog.InfoLevel, slog.Options{"duplicateKeys": "newest"}); err == nil {
+		t.Error("unknown duplicateKeys accepted")
+	}
+}
+
+func TestECSFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, slog.InfoLevel, slog.Options{"formatter": "ecs", "name": "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Warnw("failed", "http.method", "GET", "error", errors.New("timeout"), "message", "x")
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("%s: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"log":     map[string]interface{}{"level": "warn", "logger": "api"},
+		"message": "failed",
+		"ecs":     map[string]interface{}{"version": slog.ECSVersion},
+		"error":   map[string]interface{}{"message": "timeout", "type": "*errors.errorString"},
+		"fields":  map[string]interface{}{"message": "x"},
+		"http":    map[string]interface{}{"method": "GET"},
+	}
+	ts, _ := m["@timestamp"].(string)
+	if _, err := time.Parse("2006-01-02T15:04:05.000Z", ts); err != nil {
+		t.Errorf("@timestamp %q: %v", ts, err)
+	}
+	delete(m, "@timestamp")
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got  %v\nwant %v", m, want)
+	}
+}
+
+func TestGroupFlattened(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg slog.Logger)
+		want string
+	}{
+		{"group field", func(lg slog.Logger) { lg.Infow("m", slog.Group("http", "method", "GET"), "n", 1) },
+			`"http.method":"GET","n":1`},
+		{"with group", func(lg slog.Logger) { slog.WithGroup(lg, "http").Infow("m", "method", "GET") },
+			`"http.method":"GET"`},
+		{"nested with group", func(lg slog.Logger) {
+			slog.WithGroup(slog.WithGroup(lg, "http"), "req").Infow("m", "method", "GET", slog.Group("resp", "status", 200))
+		}, `"http.req.method":"GET","http.req.resp.status":200`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l, err := New(&buf, slog.InfoLevel, slog.Options{"formatter": "json", "disableTimestamp": true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.log(l)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("got %s, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitFormatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    slog.Options
+		max   int
+		want  string
+		valid bool
+	}{
+		{"json last keys dropped", slog.Options{"formatter": "json"}, 110,
+			`{"@level":"info","@msg":"hello","_truncated":"…(truncated 6 fields)","a":"value-1","b":"value-2"}` + "\n", true},
+		{"json not cut", slog.Options{"formatter": "json"}, 20,
+			`{"@level":"info","@msg":"…(truncated 5 bytes)","_truncated":"…(truncated 8 fields)"}` + "\n", true},
+		{"text cut at rune boundary", slog.Options{}, 20, `@level=info @msg="` + "\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.op["disableTimestamp"] = true
+			tt.op["maxEntryBytes"] = tt.max
+			var buf bytes.Buffer
+			l, err := New(&buf, slog.InfoLevel, tt.op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Infow("hello", "a", "value-1", "b", "value-2", "c", "value-3", "d", "value-4",
+				"e", "value-5", "f", "value-6", "g", "value-7", "h", "value-8")
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if tt.valid && !json.Valid(buf.Bytes()) {
+				t.Errorf("invalid JSON %q", buf.String())
+			}
+		})
+	}
+}
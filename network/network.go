@@ -0,0 +1,180 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ipsusila/slog"
+)
+
+// Name of network logger
+const Name = "network"
+
+type networkConstructor struct{}
+
+const (
+	fieldNetwork         = "network"
+	fieldAddress         = "address"
+	fieldFraming         = "framing"
+	fieldFormatter       = "formatter"
+	fieldTimestampFormat = "timestampFormat"
+	fieldDisableColor    = "disableColor"
+	fieldTLS             = "tls"
+	fieldTLSServerName   = "tlsServerName"
+	fieldTLSCAFile       = "tlsCAFile"
+	fieldTLSSkipVerify   = "tlsInsecureSkipVerify"
+	fieldDialTimeout     = "dialTimeout"
+	fieldWriteTimeout    = "writeTimeout"
+	fieldMinBackoff      = "minBackoff"
+	fieldMaxBackoff      = "maxBackoff"
+	fieldBufferSize      = "bufferSize"
+	fieldName            = "name"
+	fieldReportCaller    = "reportCaller"
+	fieldOnError         = "onError"
+	defaultFormatter     = "json"
+)
+
+// options supported by network logger
+var networkSchema = slog.Schema{
+	{Name: fieldNetwork, Type: slog.StringOption, Default: "tcp", Description: "network",
+		Values: []string{"tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix"}},
+	{Name: fieldAddress, Type: slog.StringOption, Default: "", Description: "collector address (host:port)"},
+	{Name: fieldFraming, Type: slog.StringOption, Default: "newline", Description: "message framing",
+		Values: []string{"newline", "length", "null"}},
+	{Name: fieldFormatter, Type: slog.StringOption, Default: defaultFormatter, Description: "output format",
+		Values: []string{"text", "json", "logfmt", "ecs", "gcp"}},
+	{Name: fieldTimestampFormat, Type: slog.StringOption, Description: "timestamp layout format"},
+	{Name: fieldDisableColor, Type: slog.BoolOption, Default: true, Description: "disable color (text)"},
+	{Name: fieldTLS, Type: slog.BoolOption, Default: false, Description: "connect using TLS"},
+	{Name: fieldTLSServerName, Type: slog.StringOption, Description: "server name for TLS verification"},
+	{Name: fieldTLSCAFile, Type: slog.StringOption, Description: "PEM file of CA certificates"},
+	{Name: fieldTLSSkipVerify, Type: slog.BoolOption, Default: false, Description: "skip TLS certificate verification"},
+	{Name: fieldDialTimeout, Type: slog.DurationOption, Default: DefaultDialTimeout, Description: "dial timeout"},
+	{Name: fieldWriteTimeout, Type: slog.DurationOption, Default: DefaultWriteTimeout, Description: "write timeout"},
+	{Name: fieldMinBackoff, Type: slog.DurationOption, Default: DefaultMinBackoff, Description: "initial reconnect delay"},
+	{Name: fieldMaxBackoff, Type: slog.DurationOption, Default: DefaultMaxBackoff, Description: "maximum reconnect delay"},
+	{Name: fieldBufferSize, Type: slog.IntOption, Default: DefaultBufferSize, Description: "maximum bytes buffered while disconnected"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name written in each entry"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add caller to each entry"},
+	{Name: fieldOnError, Type: slog.ErrorHandlerOption, Description: "func(error) receiving connection and write errors"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &networkConstructor{})
+}
+
+// New creates network logger. If w is not nil, entries are written to w,
+// otherwise entries are written to network writer configured by options.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := networkSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		cfg, err := ConfigFromOptions(op)
+		if err != nil {
+			return nil, err
+		}
+		w = NewWriter(cfg)
+	}
+
+	// encoder, json by default and without color for text
+	eop := slog.Options{
+		fieldFormatter:    op.GetString(fieldFormatter, defaultFormatter),
+		fieldDisableColor: op.GetBool(fieldDisableColor, true),
+	}
+	if ts, ok := op[fieldTimestampFormat]; ok {
+		eop[fieldTimestampFormat] = ts
+	}
+	if err := slog.CheckEncoderOptions(eop); err != nil {
+		return nil, err
+	}
+	enc := slog.NewEncoder(eop)
+
+	lg := slog.NewHandlerLogger(slog.NewWriterHandler(w, enc), 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// ConfigFromOptions creates writer configuration from options
+func ConfigFromOptions(op slog.Options) (Config, error) {
+	cfg := Config{
+		Network:      strings.ToLower(op.GetString(fieldNetwork, "tcp")),
+		Address:      op.GetString(fieldAddress, ""),
+		Framing:      framingStrMap[strings.ToLower(op.GetString(fieldFraming, "newline"))],
+		DialTimeout:  op.GetDuration(fieldDialTimeout, DefaultDialTimeout),
+		WriteTimeout: op.GetDuration(fieldWriteTimeout, DefaultWriteTimeout),
+		MinBackoff:   op.GetDuration(fieldMinBackoff, DefaultMinBackoff),
+		MaxBackoff:   op.GetDuration(fieldMaxBackoff, DefaultMaxBackoff),
+		BufferSize:   op.GetInt(fieldBufferSize, DefaultBufferSize),
+	}
+	onError, err := op.GetErrorHandler(fieldOnError)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.OnError = onError
+	if cfg.Address == "" {
+		return cfg, errors.New("network: address is required")
+	}
+
+	if op.GetBool(fieldTLS, false) {
+		tc := &tls.Config{
+			ServerName:         op.GetString(fieldTLSServerName, ""),
+			InsecureSkipVerify: op.GetBool(fieldTLSSkipVerify, false),
+		}
+		if caFile := op.GetString(fieldTLSCAFile, ""); caFile != "" {
+			pem, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				return cfg, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return cfg, errors.New("network: no certificate found in " + caFile)
+			}
+			tc.RootCAs = pool
+		}
+		cfg.TLS = tc
+	}
+
+	return cfg, nil
+}
+
+func (c *networkConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *networkConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by network logger
+func (c *networkConstructor) Schema() slog.Schema {
+	return networkSchema
+}
@@ -0,0 +1,321 @@
+package network
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Framing of each message written to the connection
+type Framing int
+
+// Supported framing
+const (
+	// NewlineFraming terminates each message with a new line
+	NewlineFraming Framing = iota
+	// LengthPrefixFraming writes 4-byte big endian length before each message
+	LengthPrefixFraming
+	// NullFraming terminates each message with null byte (e.g. GELF over TCP)
+	NullFraming
+)
+
+var framingStrMap = map[string]Framing{
+	"newline": NewlineFraming,
+	"length":  LengthPrefixFraming,
+	"null":    NullFraming,
+}
+
+// Default configuration values
+const (
+	DefaultDialTimeout  = 5 * time.Second
+	DefaultWriteTimeout = 5 * time.Second
+	DefaultMinBackoff   = 100 * time.Millisecond
+	DefaultMaxBackoff   = 30 * time.Second
+	DefaultBufferSize   = 1 << 20
+)
+
+// ErrClosed is returned when writing to closed writer
+var ErrClosed = errors.New("network: writer closed")
+
+// Config of network writer, zero value uses defaults
+type Config struct {
+	Network      string
+	Address      string
+	Framing      Framing
+	TLS          *tls.Config
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	// BufferSize is maximum bytes kept while disconnected, oldest messages are dropped
+	BufferSize int
+	// OnError receives connection and write errors, default to slog.ReportError.
+	// It is called without lock held, so it may log to the same sink.
+	OnError slog.ErrorHandler
+}
+
+// Writer writes framed messages to network connection.
+// While disconnected, messages are kept in memory buffer and the connection
+// is re-established in background using exponential backoff.
+type Writer struct {
+	cfg Config
+
+	mu           sync.Mutex
+	conn         net.Conn
+	queue        [][]byte
+	queued       int
+	dropped      int
+	reconnecting bool
+	closed       bool
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewWriter creates writer and connects to the address.
+// If the first connection fails, it is retried in background.
+func NewWriter(cfg Config) *Writer {
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = DefaultDialTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = slog.ReportError
+	}
+
+	w := &Writer{
+		cfg:  cfg,
+		done: make(chan struct{}),
+	}
+	conn, err := w.dial()
+	w.mu.Lock()
+	if err != nil {
+		w.startReconnect()
+	} else {
+		w.conn = conn
+	}
+	w.mu.Unlock()
+	if err != nil {
+		w.cfg.OnError(err)
+	}
+
+	return w
+}
+
+func (w *Writer) dial() (net.Conn, error) {
+	d := &net.Dialer{Timeout: w.cfg.DialTimeout}
+	var conn net.Conn
+	var err error
+	if w.cfg.TLS != nil {
+		conn, err = tls.DialWithDialer(d, w.cfg.Network, w.cfg.Address, w.cfg.TLS)
+	} else {
+		conn, err = d.Dial(w.cfg.Network, w.cfg.Address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("network: %w", err)
+	}
+	return conn, nil
+}
+
+func (w *Writer) frame(p []byte) []byte {
+	switch w.cfg.Framing {
+	case LengthPrefixFraming:
+		msg := make([]byte, 4+len(p))
+		binary.BigEndian.PutUint32(msg, uint32(len(p)))
+		copy(msg[4:], p)
+		return msg
+	case NullFraming:
+		msg := make([]byte, len(p)+1)
+		copy(msg, p)
+		return msg
+	default:
+		n := len(p)
+		if n > 0 && p[n-1] == '\n' {
+			return append([]byte(nil), p...)
+		}
+		msg := make([]byte, n+1)
+		copy(msg, p)
+		msg[n] = '\n'
+		return msg
+	}
+}
+
+func (w *Writer) send(conn net.Conn, msg []byte) error {
+	if w.cfg.WriteTimeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteTimeout))
+	}
+	_, err := conn.Write(msg)
+	return err
+}
+
+// enqueue message, drop oldest messages if buffer is full
+func (w *Writer) enqueue(msg []byte) {
+	w.queue = append(w.queue, msg)
+	w.queued += len(msg)
+	for w.queued > w.cfg.BufferSize && len(w.queue) > 0 {
+		w.queued -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		w.dropped++
+	}
+}
+
+// Write single message. When disconnected the message is buffered and
+// nil error is returned, failures are reported to OnError.
+func (w *Writer) Write(p []byte) (int, error) {
+	err := w.write(w.frame(p))
+	if err == ErrClosed {
+		return 0, err
+	}
+	if err != nil {
+		w.cfg.OnError(err)
+	}
+	return len(p), nil
+}
+
+// write or buffer framed message, return send error to be reported after unlock
+func (w *Writer) write(msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrClosed
+	}
+
+	var err error
+	if w.conn != nil {
+		if err = w.send(w.conn, msg); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		err = fmt.Errorf("network: write: %w", err)
+	}
+	w.enqueue(msg)
+	w.startReconnect()
+
+	return err
+}
+
+// must be called with lock held
+func (w *Writer) startReconnect() {
+	if w.reconnecting || w.closed {
+		return
+	}
+	w.reconnecting = true
+	w.wg.Add(1)
+	go w.reconnect()
+}
+
+func (w *Writer) reconnect() {
+	defer w.wg.Done()
+
+	backoff := w.cfg.MinBackoff
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-timer.C:
+		}
+
+		if w.tryReconnect() {
+			return
+		}
+		backoff *= 2
+		if backoff > w.cfg.MaxBackoff {
+			backoff = w.cfg.MaxBackoff
+		}
+		timer.Reset(backoff)
+	}
+}
+
+// connect and flush buffered messages, return true on success
+func (w *Writer) tryReconnect() bool {
+	conn, err := w.dial()
+	if err != nil {
+		w.cfg.OnError(err)
+		return false
+	}
+
+	ok, errs := w.resume(conn)
+	for _, err := range errs {
+		w.cfg.OnError(err)
+	}
+	return ok
+}
+
+// resume flushes buffered messages to conn and keeps it as connection,
+// errors are returned to be reported after unlock
+func (w *Writer) resume(conn net.Conn) (bool, []error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		conn.Close()
+		return true, nil
+	}
+	var errs []error
+	if w.dropped > 0 {
+		errs = append(errs, fmt.Errorf("network: %d messages dropped while disconnected", w.dropped))
+		w.dropped = 0
+	}
+	for len(w.queue) > 0 {
+		if err := w.send(conn, w.queue[0]); err != nil {
+			conn.Close()
+			return false, append(errs, fmt.Errorf("network: write: %w", err))
+		}
+		w.queued -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+	}
+	w.conn = conn
+	w.reconnecting = false
+
+	return true, errs
+}
+
+// Connected return true if connection is established
+func (w *Writer) Connected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn != nil
+}
+
+// Close the connection and stop reconnecting, buffered messages are discarded
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	var err error
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	return err
+}
@@ -0,0 +1,307 @@
+package network
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// errRecorder collects errors passed to OnError
+type errRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *errRecorder) handle(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *errRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.errs)
+}
+
+func (r *errRecorder) contains(s string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, err := range r.errs {
+		if strings.Contains(err.Error(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// freeAddr return local tcp address without listener
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func listen(t *testing.T, addr string) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
+
+// accept connection within timeout
+func accept(t *testing.T, ln net.Listener) net.Conn {
+	t.Helper()
+	ln.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func TestFraming(t *testing.T) {
+	tests := []struct {
+		name    string
+		framing Framing
+		msgs    []string
+		want    string
+	}{
+		{"newline", NewlineFraming, []string{"a", "b\n"}, "a\nb\n"},
+		{"length", LengthPrefixFraming, []string{"ab", "c"}, "\x00\x00\x00\x02ab\x00\x00\x00\x01c"},
+		{"null", NullFraming, []string{"a", "b"}, "a\x00b\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln := listen(t, "127.0.0.1:0")
+			w := NewWriter(Config{Address: ln.Addr().String(), Framing: tt.framing})
+			defer w.Close()
+			conn := accept(t, ln)
+
+			for _, m := range tt.msgs {
+				if _, err := w.Write([]byte(m)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := make([]byte, len(tt.want))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		msgs       []string
+		want       []string
+		dropped    bool
+	}{
+		{"buffered until connected", 0, []string{"one", "two", "three"}, []string{"one", "two", "three"}, false},
+		{"oldest dropped on overflow", 8, []string{"one", "two", "six"}, []string{"two", "six"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := freeAddr(t)
+			rec := &errRecorder{}
+			w := NewWriter(Config{
+				Address:    addr,
+				MinBackoff: 10 * time.Millisecond,
+				MaxBackoff: 20 * time.Millisecond,
+				BufferSize: tt.bufferSize,
+				OnError:    rec.handle,
+			})
+			defer w.Close()
+			if w.Connected() {
+				t.Fatal("connected without listener")
+			}
+			for _, m := range tt.msgs {
+				if _, err := w.Write([]byte(m)); err != nil {
+					t.Fatalf("write while disconnected: %v", err)
+				}
+			}
+
+			conn := accept(t, listen(t, addr))
+			r := bufio.NewReader(conn)
+			for _, want := range tt.want {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.TrimSuffix(line, "\n"); got != want {
+					t.Errorf("got %q, want %q", got, want)
+				}
+			}
+			if got := rec.contains("dropped while disconnected"); got != tt.dropped {
+				t.Errorf("drop reported = %v, want %v", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	rec := &errRecorder{}
+	w := NewWriter(Config{
+		Address:    freeAddr(t),
+		MinBackoff: 20 * time.Millisecond,
+		MaxBackoff: time.Hour,
+		OnError:    rec.handle,
+	})
+	defer w.Close()
+
+	// dial attempts at 0, 20, 60, 140 and 300ms, constant delay would make 13 attempts
+	time.Sleep(250 * time.Millisecond)
+	if n := rec.count(); n < 2 || n > 6 {
+		t.Errorf("%d dial attempts in 250ms, want exponential backoff", n)
+	}
+}
+
+func TestReconnectAfterPeerClose(t *testing.T) {
+	ln := listen(t, "127.0.0.1:0")
+	rec := &errRecorder{}
+	w := NewWriter(Config{
+		Address:    ln.Addr().String(),
+		MinBackoff: 10 * time.Millisecond,
+		OnError:    rec.handle,
+	})
+	defer w.Close()
+	accept(t, ln).Close()
+
+	// writes fail once the peer close is detected, then the writer reconnects
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	deadline := time.After(5 * time.Second)
+	var conn net.Conn
+	for conn == nil {
+		w.Write([]byte("ping"))
+		select {
+		case conn = <-accepted:
+		case <-time.After(5 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("writer did not reconnect")
+		}
+	}
+	defer conn.Close()
+	if !rec.contains("write") {
+		t.Error("write error not reported")
+	}
+
+	w.Write([]byte("after"))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "after\n" {
+			break
+		}
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	w := NewWriter(Config{Address: freeAddr(t), OnError: func(error) {}})
+	w.Close()
+	if _, err := w.Write([]byte("msg")); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestConfigFromOptionsOnError(t *testing.T) {
+	tests := []struct {
+		name    string
+		onError interface{}
+		wantErr bool
+	}{
+		{"func", func(error) {}, false},
+		{"error handler", slog.ErrorHandler(func(error) {}), false},
+		{"wrong signature", func(err error) error { return err }, true},
+		{"not a function", "stderr", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := slog.Options{"address": "127.0.0.1:1", "onError": tt.onError}
+			cfg, err := ConfigFromOptions(op)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigFromOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.OnError == nil {
+				t.Error("OnError not set")
+			}
+			if err := networkSchema.Validate(op); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOnErrorWritesBack(t *testing.T) {
+	ln := listen(t, "127.0.0.1:0")
+	var mu sync.Mutex
+	var w *Writer
+	reported := make(chan struct{}, 1)
+	nw := NewWriter(Config{
+		Address:    ln.Addr().String(),
+		MinBackoff: time.Hour,
+		OnError: func(err error) {
+			mu.Lock()
+			lw := w
+			mu.Unlock()
+			// error handler writing to the same writer must not deadlock
+			lw.Write([]byte("error: " + err.Error()))
+			select {
+			case reported <- struct{}{}:
+			default:
+			}
+		},
+	})
+	mu.Lock()
+	w = nw
+	mu.Unlock()
+	defer w.Close()
+	accept(t, ln).Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			w.Write([]byte("ping"))
+			select {
+			case <-reported:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("write deadlocked while reporting error")
+	}
+}
@@ -1,16 +1,79 @@
 package slog
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Options stores additional log configuration
 type Options map[string]interface{}
 
+// OptionType describes expected type of an option value
+type OptionType int
+
+// Supported option types
+const (
+	AnyOption OptionType = iota
+	StringOption
+	IntOption
+	FloatOption
+	BoolOption
+	OptionsOption
+	DurationOption
+	// ErrorHandlerOption accepts ErrorHandler or func(error)
+	ErrorHandlerOption
+)
+
+var optTypeStrMap = map[OptionType]string{
+	AnyOption:          "any",
+	StringOption:       "string",
+	IntOption:          "int",
+	FloatOption:        "float",
+	BoolOption:         "bool",
+	OptionsOption:      "options",
+	DurationOption:     "duration",
+	ErrorHandlerOption: "func(error)",
+}
+
+// OptionSpec describes single option supported by a logger.
+// Values lists accepted values of string option (matched case-insensitively,
+// consumers lowercase the value), empty means any value.
+type OptionSpec struct {
+	Name        string
+	Type        OptionType
+	Default     interface{}
+	Description string
+	Values      []string
+}
+
+// Schema lists options supported by a logger
+type Schema []OptionSpec
+
+// SchemaProvider is implemented by constructor which declares its options.
+// Options passed to NewWithOptions are validated against the schema.
+type SchemaProvider interface {
+	Schema() Schema
+}
+
+// Stringer interface
+func (t OptionType) String() string {
+	if str, ok := optTypeStrMap[t]; ok {
+		return str
+	}
+	return "unknown"
+}
+
 // Get configuration value or default if doesn't exist
 func (op Options) Get(key string, def interface{}) interface{} {
 	if val, ok := op[key]; ok {
 		return val
 	}
-	return key
+	return def
 }
 
 // GetOptions get value as map[string]interface{}
@@ -54,14 +117,9 @@ func (op Options) GetString(key string, def string) string {
 		return def
 	}
 
-	if s, ok := val.(string); ok {
+	if s, ok := toString(val); ok {
 		return s
-	} else if sp, ok := val.(*string); ok {
-		return *sp
-	} else if sf, ok := val.(interface{ String() string }); ok {
-		return sf.String()
 	}
-
 	return def
 }
 
@@ -72,89 +130,355 @@ func (op Options) GetInt(key string, def int) int {
 		return def
 	}
 
+	if i, ok := toInt(val); ok {
+		return int(i)
+	}
+	return def
+}
+
+// GetFloat get floating point value from options with given key
+func (op Options) GetFloat(key string, def float64) float64 {
+	val, ok := op[key]
+	if !ok {
+		return def
+	}
+
+	if f, ok := toFloat(val); ok {
+		return f
+	}
+	return def
+}
+
+// GetBool get boolean value from options with given key
+func (op Options) GetBool(key string, def bool) bool {
+	val, ok := op[key]
+	if !ok {
+		return def
+	}
+
+	if b, ok := toBool(val); ok {
+		return b
+	}
+	return def
+}
+
+// GetDuration get duration from options with given key.
+// Value may be time.Duration, string parsed by time.ParseDuration or number of seconds.
+func (op Options) GetDuration(key string, def time.Duration) time.Duration {
+	val, ok := op[key]
+	if !ok {
+		return def
+	}
+
+	if d, ok := toDuration(val); ok {
+		return d
+	}
+	return def
+}
+
+// GetErrorHandler get ErrorHandler or func(error) from options with given key,
+// value of other type is returned as error
+func (op Options) GetErrorHandler(key string) (ErrorHandler, error) {
+	val, ok := op[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if fn, ok := toErrorHandler(val); ok {
+		return fn, nil
+	}
+	return nil, fmt.Errorf("option %q expects %v, got %T", key, ErrorHandlerOption, val)
+}
+
+// Lookup option specification with given name
+func (s Schema) Lookup(name string) (OptionSpec, bool) {
+	for _, spec := range s {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return OptionSpec{}, false
+}
+
+// Names return name of all options in the schema
+func (s Schema) Names() []string {
+	names := make([]string, len(s))
+	for i, spec := range s {
+		names[i] = spec.Name
+	}
+	return names
+}
+
+// Defaults return options filled with default value of each option
+func (s Schema) Defaults() Options {
+	op := make(Options)
+	for _, spec := range s {
+		if spec.Default != nil {
+			op[spec.Name] = spec.Default
+		}
+	}
+	return op
+}
+
+// Validate options against the schema.
+// Unknown keys and values with wrong type are reported as error.
+func (s Schema) Validate(op Options) error {
+	if len(op) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(op))
+	for key := range op {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var msgs []string
+	for _, key := range keys {
+		spec, ok := s.Lookup(key)
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("unknown option %q", key))
+			continue
+		}
+		if !spec.Accepts(op[key]) {
+			if len(spec.Values) != 0 {
+				msgs = append(msgs, fmt.Sprintf("option %q expects one of [%s], got %v",
+					key, strings.Join(spec.Values, ", "), op[key]))
+			} else {
+				msgs = append(msgs, fmt.Sprintf("option %q expects %v, got %T", key, spec.Type, op[key]))
+			}
+		}
+	}
+	if len(msgs) != 0 {
+		return errors.New("invalid options: " + strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
+
+// Accepts return true if val can be used as value of the option
+func (spec OptionSpec) Accepts(val interface{}) bool {
+	var ok bool
+	switch spec.Type {
+	case AnyOption:
+		ok = true
+	case StringOption:
+		var str string
+		str, ok = toString(val)
+		if ok && len(spec.Values) != 0 {
+			ok = false
+			for _, v := range spec.Values {
+				if strings.EqualFold(v, str) {
+					ok = true
+					break
+				}
+			}
+		}
+	case IntOption:
+		_, ok = toInt(val)
+	case FloatOption:
+		_, ok = toFloat(val)
+	case BoolOption:
+		_, ok = toBool(val)
+	case DurationOption:
+		_, ok = toDuration(val)
+	case ErrorHandlerOption:
+		_, ok = toErrorHandler(val)
+	case OptionsOption:
+		switch val.(type) {
+		case Options, map[string]interface{}:
+			ok = true
+		}
+	}
+	return ok
+}
+
+// SupportedOptions return options schema of logger with given name
+func SupportedOptions(name string) (Schema, error) {
+	c, ok := ConstructorFor(name)
+	if !ok {
+		return nil, errors.New("unknown logger: " + name)
+	}
+	if sp, ok := c.(SchemaProvider); ok {
+		return sp.Schema(), nil
+	}
+	return nil, nil
+}
+
+func toString(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case *string:
+		return *v, true
+	}
+	return "", false
+}
+
+func toErrorHandler(val interface{}) (ErrorHandler, bool) {
+	switch v := val.(type) {
+	case nil:
+		return nil, true
+	case ErrorHandler:
+		return v, true
+	case func(error):
+		return v, true
+	}
+	return nil, false
+}
+
+func toFloat(val interface{}) (float64, bool) {
 	switch v := val.(type) {
 	case int8:
-		return int(v)
+		return float64(v), true
 	case uint8:
-		return int(v)
+		return float64(v), true
 	case int16:
-		return int(v)
+		return float64(v), true
 	case uint16:
-		return int(v)
+		return float64(v), true
 	case int32:
-		return int(v)
+		return float64(v), true
+	case uint32:
+		return float64(v), true
 	case int:
-		return v
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
 	case *int8:
-		return int(*v)
+		return float64(*v), true
 	case *uint8:
-		return int(*v)
+		return float64(*v), true
 	case *int16:
-		return int(*v)
+		return float64(*v), true
 	case *uint16:
-		return int(*v)
+		return float64(*v), true
 	case *int32:
-		return int(*v)
+		return float64(*v), true
+	case *uint32:
+		return float64(*v), true
 	case *int:
-		return *v
+		return float64(*v), true
+	case *uint:
+		return float64(*v), true
+	case *int64:
+		return float64(*v), true
+	case *uint64:
+		return float64(*v), true
+	case *float32:
+		return float64(*v), true
+	case *float64:
+		return *v, true
 	}
-
-	return def
+	return 0, false
 }
 
-// GetBool get boolean value from options with given key
-func (op Options) GetBool(key string, def bool) bool {
-	val, ok := op[key]
-	if !ok {
-		return def
-	}
-
+// toInt converts integer kinds without going through float64,
+// which loses precision above 2^53. Float value must be integral.
+func toInt(val interface{}) (int64, bool) {
 	switch v := val.(type) {
-	case bool:
-		return v
-	case *bool:
-		return *v
 	case int8:
-		return v != 0
+		return int64(v), true
 	case uint8:
-		return v != 0
+		return int64(v), true
 	case int16:
-		return v != 0
+		return int64(v), true
 	case uint16:
-		return v != 0
+		return int64(v), true
 	case int32:
-		return v != 0
+		return int64(v), true
+	case uint32:
+		return int64(v), true
 	case int:
-		return v != 0
-	case uint:
-		return v != 0
+		return int64(v), true
 	case int64:
-		return v != 0
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
 	case uint64:
-		return v != 0
+		return int64(v), v <= math.MaxInt64
 	case *int8:
-		return *v != 0
+		return int64(*v), true
 	case *uint8:
-		return *v != 0
+		return int64(*v), true
 	case *int16:
-		return *v != 0
+		return int64(*v), true
 	case *uint16:
-		return *v != 0
+		return int64(*v), true
 	case *int32:
-		return *v != 0
+		return int64(*v), true
+	case *uint32:
+		return int64(*v), true
 	case *int:
-		return *v != 0
-	case *uint:
-		return *v != 0
+		return int64(*v), true
 	case *int64:
-		return *v != 0
+		return *v, true
+	case *uint:
+		return int64(*v), uint64(*v) <= math.MaxInt64
 	case *uint64:
-		return *v != 0
+		return int64(*v), *v <= math.MaxInt64
+	}
+	if f, ok := toFloat(val); ok && f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return int64(f), true
+	}
+	return 0, false
+}
+
+func toDuration(val interface{}) (time.Duration, bool) {
+	switch v := val.(type) {
+	case time.Duration:
+		return v, true
+	case *time.Duration:
+		return *v, true
 	case string:
-		return strings.EqualFold("true", v) || strings.EqualFold("yes", v)
+		d, err := time.ParseDuration(v)
+		return d, err == nil
 	case *string:
-		return strings.EqualFold("true", *v) || strings.EqualFold("yes", *v)
+		d, err := time.ParseDuration(*v)
+		return d, err == nil
 	}
 
-	return def
+	if f, ok := toFloat(val); ok {
+		return time.Duration(f * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+func toBool(val interface{}) (bool, bool) {
+	switch v := val.(type) {
+	case bool:
+		return v, true
+	case *bool:
+		return *v, true
+	case string:
+		return parseBool(v)
+	case *string:
+		return parseBool(*v)
+	}
+
+	if f, ok := toFloat(val); ok {
+		return f != 0, true
+	}
+	return false, false
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, true
+	case "no", "off":
+		return false, true
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, true
+	}
+	return false, false
 }
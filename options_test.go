@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOptionSpecAccepts(t *testing.T) {
+	enum := OptionSpec{Name: "mode", Type: StringOption, Values: []string{"threshold", "mask"}}
+	tests := []struct {
+		name string
+		spec OptionSpec
+		val  interface{}
+		want bool
+	}{
+		{"enum exact", enum, "mask", true},
+		{"enum other case", enum, "Mask", true},
+		{"enum unknown", enum, "bits", false},
+		{"string", OptionSpec{Type: StringOption}, "text", true},
+		{"stringer is not string", OptionSpec{Type: StringOption}, time.Second, false},
+		{"error handler", OptionSpec{Type: ErrorHandlerOption}, func(error) {}, true},
+		{"typed error handler", OptionSpec{Type: ErrorHandlerOption}, ErrorHandler(func(error) {}), true},
+		{"wrong error handler", OptionSpec{Type: ErrorHandlerOption}, func(string) {}, false},
+		{"int", OptionSpec{Type: IntOption}, 10, true},
+		{"int from integral float", OptionSpec{Type: IntOption}, 10.0, true},
+		{"int from fraction", OptionSpec{Type: IntOption}, 10.5, false},
+		{"int64 above 2^53", OptionSpec{Type: IntOption}, int64(1<<53 + 1), true},
+		{"uint64 overflow", OptionSpec{Type: IntOption}, uint64(math.MaxUint64), false},
+		{"bool string", OptionSpec{Type: BoolOption}, "yes", true},
+		{"duration", OptionSpec{Type: DurationOption}, "1s", true},
+		{"options", OptionSpec{Type: OptionsOption}, map[string]interface{}{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.Accepts(tt.val); got != tt.want {
+				t.Errorf("Accepts(%v) = %v, want %v", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsGetInt(t *testing.T) {
+	big := int64(1<<53 + 1)
+	tests := []struct {
+		name string
+		val  interface{}
+		want int
+	}{
+		{"int", 7, 7},
+		{"int64 exact", big, int(big)},
+		{"pointer", &big, int(big)},
+		{"float", 3.0, 3},
+		{"fraction uses default", 3.5, -1},
+		{"string uses default", "3", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := Options{"n": tt.val}
+			if got := op.GetInt("n", -1); got != tt.want {
+				t.Errorf("GetInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      Options
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"valid", Options{"levelMode": "mask", "disableColor": true}, false},
+		{"unknown key", Options{"colour": true}, true},
+		{"wrong type", Options{"disableColor": "maybe"}, true},
+		{"enum case", Options{"levelMode": "MASK"}, false},
+		{"enum unknown", Options{"levelMode": "bits"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := stdLoggerSchema.Validate(tt.op)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnumOptionsCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Options
+		want string
+	}{
+		{"lower", Options{"formatter": "json", "duplicateKeys": "first"}, `{"level":"info","msg":"m","k":1}` + "\n"},
+		{"mixed", Options{"formatter": "JSON", "duplicateKeys": "First"}, `{"level":"info","msg":"m","k":1}` + "\n"},
+		{"upper logfmt", Options{"formatter": "LOGFMT", "duplicateKeys": "SUFFIX"}, "level=info msg=m k=1 k_2=2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.op["timestampFormat"] = "none"
+			tt.op["color"] = "Never"
+			lg, err := NewStdLogger(&buf, InfoLevel, tt.op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg.Infow("m", "k", 1, "k", 2)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
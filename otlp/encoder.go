@@ -0,0 +1,282 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/httplog"
+)
+
+// Severity numbers of OpenTelemetry logs data model
+const (
+	SeverityTrace = 1
+	SeverityDebug = 5
+	SeverityInfo  = 9
+	SeverityWarn  = 13
+	SeverityError = 17
+	SeverityFatal = 21
+	severityMax   = 24
+)
+
+// SeverityNumber maps level to OpenTelemetry severity number.
+// Each built-in level maps to the first number of its range,
+// user defined levels are placed inside the range by severity.
+func SeverityNumber(lv slog.Level) int {
+	sev := lv.Severity() - slog.TraceSeverity
+	if sev < 0 {
+		return SeverityTrace
+	}
+	n := SeverityTrace + 4*(sev/100) + 4*(sev%100)/100
+	if n > severityMax {
+		n = severityMax
+	}
+	return n
+}
+
+// SeverityText of level, uppercase level name
+func SeverityText(lv slog.Level) string {
+	return strings.ToUpper(lv.String())
+}
+
+// OTLP/JSON structures
+
+type anyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *string      `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	BytesValue  *string      `json:"bytesValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+	Flags                int        `json:"flags,omitempty"`
+}
+
+type scope struct {
+	Name string `json:"name,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope             `json:"scope"`
+	LogRecords []json.RawMessage `json:"logRecords"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type resourceLogs struct {
+	Resource  resource     `json:"resource"`
+	ScopeLogs []*scopeLogs `json:"scopeLogs"`
+}
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+// maximum nesting of attribute value
+const maxValueDepth = 8
+
+func stringValue(s string) anyValue {
+	return anyValue{StringValue: &s}
+}
+
+func intValue(i int64) anyValue {
+	s := strconv.FormatInt(i, 10)
+	return anyValue{IntValue: &s}
+}
+
+// convert value into attribute value, unsupported types and values nested
+// deeper than maximum depth are written as string
+func toAnyValue(val interface{}, depth int) anyValue {
+	switch v := val.(type) {
+	case nil:
+		return anyValue{}
+	case string:
+		return stringValue(v)
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int:
+		return intValue(int64(v))
+	case int8:
+		return intValue(int64(v))
+	case int16:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint:
+		return toAnyValue(uint64(v), depth)
+	case uint8:
+		return intValue(int64(v))
+	case uint16:
+		return intValue(int64(v))
+	case uint32:
+		return intValue(int64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			return stringValue(strconv.FormatUint(v, 10))
+		}
+		return intValue(int64(v))
+	case float32:
+		return toAnyValue(float64(v), depth)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return stringValue(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return anyValue{DoubleValue: &v}
+	case []byte:
+		s := base64.StdEncoding.EncodeToString(v)
+		return anyValue{BytesValue: &s}
+	case error:
+		return stringValue(v.Error())
+	case time.Duration:
+		return stringValue(v.String())
+	case []interface{}:
+		if depth < maxValueDepth {
+			arr := &arrayValue{Values: make([]anyValue, 0, len(v))}
+			for _, item := range v {
+				arr.Values = append(arr.Values, toAnyValue(item, depth+1))
+			}
+			return anyValue{ArrayValue: arr}
+		}
+	case map[string]interface{}:
+		if depth < maxValueDepth {
+			return anyValue{KvlistValue: &kvlistValue{Values: toKeyValues(v, depth+1)}}
+		}
+	case slog.Object:
+		if depth < maxValueDepth {
+			kvs := make([]keyValue, 0, len(v))
+			for _, f := range v {
+				kvs = append(kvs, keyValue{Key: f.Key, Value: toAnyValue(f.Value, depth+1)})
+			}
+			return anyValue{KvlistValue: &kvlistValue{Values: kvs}}
+		}
+	}
+	str, _ := slog.AsString(val)
+	return stringValue(str)
+}
+
+// map converted into attributes sorted by key
+func toKeyValues(m map[string]interface{}, depth int) []keyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, keyValue{Key: k, Value: toAnyValue(m[k], depth)})
+	}
+	return kvs
+}
+
+// Encoder encodes entries as OTLP/JSON logs export request.
+// Logger name is used as instrumentation scope name.
+type Encoder struct {
+	// Resource attributes, e.g. service.name
+	Resource map[string]interface{}
+}
+
+// ContentType of export request
+func (enc *Encoder) ContentType() string {
+	return "application/json"
+}
+
+// Encode single entry as export request line
+func (enc *Encoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	var rb bytes.Buffer
+	if err := enc.EncodeRecord(&rb, e); err != nil {
+		return err
+	}
+	return enc.EncodePayload(buf, []httplog.Record{{Logger: e.Logger, Data: rb.Bytes()}})
+}
+
+// EncodeRecord writes log record of the entry as JSON, observed time is the time of encoding
+func (enc *Encoder) EncodeRecord(buf *bytes.Buffer, e *slog.Entry) error {
+	observed := strconv.FormatInt(slog.Now().UnixNano(), 10)
+	b, err := json.Marshal(newLogRecord(e, observed))
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// EncodePayload of records, grouped into scopes by logger name
+func (enc *Encoder) EncodePayload(buf *bytes.Buffer, records []httplog.Record) error {
+	rl := resourceLogs{Resource: resource{Attributes: toKeyValues(enc.Resource, 0)}}
+	scopes := make(map[string]*scopeLogs)
+	for _, r := range records {
+		sl, ok := scopes[r.Logger]
+		if !ok {
+			sl = &scopeLogs{Scope: scope{Name: r.Logger}}
+			scopes[r.Logger] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+		sl.LogRecords = append(sl.LogRecords, json.RawMessage(r.Data))
+	}
+
+	return json.NewEncoder(buf).Encode(exportRequest{ResourceLogs: []resourceLogs{rl}})
+}
+
+func newLogRecord(e *slog.Entry, observed string) *logRecord {
+	rec := &logRecord{
+		TimeUnixNano:         strconv.FormatInt(e.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: observed,
+		SeverityNumber:       SeverityNumber(e.Level),
+		SeverityText:         SeverityText(e.Level),
+		Body:                 stringValue(e.Message),
+	}
+	if e.Caller != nil {
+		rec.Attributes = append(rec.Attributes,
+			keyValue{Key: "code.filepath", Value: stringValue(e.Caller.File)},
+			keyValue{Key: "code.lineno", Value: toAnyValue(e.Caller.Line, 0)},
+		)
+		if e.Caller.Function != "" {
+			rec.Attributes = append(rec.Attributes, keyValue{Key: "code.function", Value: stringValue(e.Caller.Function)})
+		}
+	}
+	for _, f := range e.Fields {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: f.Key, Value: toAnyValue(f.Value, 0)})
+	}
+	if sc, ok := e.Span(); ok {
+		rec.TraceID = sc.TraceID
+		rec.SpanID = sc.SpanID
+		if sc.Sampled {
+			rec.Flags = 1
+		}
+	}
+	return rec
+}
@@ -0,0 +1,144 @@
+package otlp
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ipsusila/slog"
+	"github.com/ipsusila/slog/httplog"
+)
+
+// Name of OTLP logger
+const Name = "otlp"
+
+// DefaultEndpoint of OTLP/HTTP logs receiver
+const DefaultEndpoint = "http://localhost:4318/v1/logs"
+
+type otlpConstructor struct{}
+
+const (
+	fieldEndpoint      = "endpoint"
+	fieldServiceName   = "serviceName"
+	fieldResource      = "resource"
+	fieldHeaders       = "headers"
+	fieldBatchSize     = "batchSize"
+	fieldBatchBytes    = "batchBytes"
+	fieldFlushInterval = "flushInterval"
+	fieldQueueSize     = "queueSize"
+	fieldGzip          = "gzip"
+	fieldMaxRetries    = "maxRetries"
+	fieldMinBackoff    = "minBackoff"
+	fieldMaxBackoff    = "maxBackoff"
+	fieldTimeout       = "timeout"
+	fieldName          = "name"
+	fieldReportCaller  = "reportCaller"
+	fieldOnError       = "onError"
+	attrServiceName    = "service.name"
+)
+
+// options supported by OTLP logger
+var otlpSchema = slog.Schema{
+	{Name: fieldEndpoint, Type: slog.StringOption, Default: DefaultEndpoint, Description: "OTLP/HTTP logs endpoint"},
+	{Name: fieldServiceName, Type: slog.StringOption, Description: "service.name resource attribute, default to program name"},
+	{Name: fieldResource, Type: slog.OptionsOption, Description: "resource attributes"},
+	{Name: fieldHeaders, Type: slog.OptionsOption, Description: "additional request headers"},
+	{Name: fieldBatchSize, Type: slog.IntOption, Default: httplog.DefaultBatchSize, Description: "maximum entries per request"},
+	{Name: fieldBatchBytes, Type: slog.IntOption, Default: httplog.DefaultBatchBytes, Description: "approximate maximum bytes per request"},
+	{Name: fieldFlushInterval, Type: slog.DurationOption, Default: httplog.DefaultFlushInterval, Description: "maximum delay before pending entries are sent"},
+	{Name: fieldQueueSize, Type: slog.IntOption, Default: httplog.DefaultQueueSize, Description: "maximum pending entries, oldest are dropped"},
+	{Name: fieldGzip, Type: slog.BoolOption, Default: false, Description: "gzip request body"},
+	{Name: fieldMaxRetries, Type: slog.IntOption, Default: httplog.DefaultMaxRetries, Description: "retries on network error, 5xx and 429"},
+	{Name: fieldMinBackoff, Type: slog.DurationOption, Default: httplog.DefaultMinBackoff, Description: "initial retry delay"},
+	{Name: fieldMaxBackoff, Type: slog.DurationOption, Default: httplog.DefaultMaxBackoff, Description: "maximum retry delay"},
+	{Name: fieldTimeout, Type: slog.DurationOption, Default: httplog.DefaultTimeout, Description: "request timeout"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name, used as instrumentation scope"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add code.* attributes"},
+	{Name: fieldOnError, Type: slog.ErrorHandlerOption, Description: "func(error) receiving export errors"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &otlpConstructor{})
+}
+
+// New creates OTLP logger. If w is not nil, each entry is written to w as
+// single line export request, otherwise entries are exported in batch to endpoint.
+// Call Flush/Close on the returned *slog.HandlerLogger before the program exits.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := otlpSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	enc := NewEncoder(op)
+	var h slog.Handler
+	if w == nil {
+		// batching options are shared with HTTP logger
+		cfg, err := httplog.ConfigFromOptions(op)
+		if err != nil {
+			return nil, err
+		}
+		cfg.URL = op.GetString(fieldEndpoint, DefaultEndpoint)
+		cfg.Method = http.MethodPost
+		cfg.Encoder = enc
+		if h, err = httplog.NewHandler(cfg); err != nil {
+			return nil, err
+		}
+	} else {
+		h = slog.NewWriterHandler(w, enc)
+	}
+
+	lg := slog.NewHandlerLogger(h, 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// NewEncoder creates encoder with resource attributes from options
+func NewEncoder(op slog.Options) *Encoder {
+	res := make(map[string]interface{})
+	for k, v := range op.GetMap(fieldResource) {
+		res[k] = v
+	}
+	if name := op.GetString(fieldServiceName, ""); name != "" {
+		res[attrServiceName] = name
+	} else if _, ok := res[attrServiceName]; !ok {
+		res[attrServiceName] = filepath.Base(os.Args[0])
+	}
+	return &Encoder{Resource: res}
+}
+
+func (c *otlpConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *otlpConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by OTLP logger
+func (c *otlpConstructor) Schema() slog.Schema {
+	return otlpSchema
+}
@@ -0,0 +1,161 @@
+package otlp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+var notice = slog.MustRegisterLevel(slog.LevelSpec{Name: "otlp-notice", Fixed: "NOTIC", Severity: slog.InfoSeverity + 50})
+
+func TestSeverityNumber(t *testing.T) {
+	tests := []struct {
+		lv   slog.Level
+		want int
+		text string
+	}{
+		{slog.TraceLevel, SeverityTrace, "TRACE"},
+		{slog.DebugLevel, SeverityDebug, "DEBUG"},
+		{slog.InfoLevel, SeverityInfo, "INFO"},
+		{notice, SeverityInfo + 2, "OTLP-NOTICE"},
+		{slog.WarnLevel, SeverityWarn, "WARN"},
+		{slog.ErrorLevel, SeverityError, "ERROR"},
+		{slog.FatalLevel, SeverityFatal, "FATAL"},
+		{slog.PanicLevel, severityMax, "PANIC"},
+	}
+	for _, tt := range tests {
+		if got := SeverityNumber(tt.lv); got != tt.want {
+			t.Errorf("SeverityNumber(%v) = %d, want %d", tt.lv, got, tt.want)
+		}
+		if got := SeverityText(tt.lv); got != tt.text {
+			t.Errorf("SeverityText(%v) = %q, want %q", tt.lv, got, tt.text)
+		}
+	}
+}
+
+// collector records export requests
+type collector struct {
+	mu       sync.Mutex
+	requests []exportRequest
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req exportRequest
+	if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.mu.Unlock()
+}
+
+func attrs(kvs []keyValue) map[string]anyValue {
+	m := make(map[string]anyValue, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func str(v anyValue) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestExport(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	lg, err := New(nil, slog.TraceLevel, slog.Options{
+		"endpoint":      srv.URL + "/v1/logs",
+		"serviceName":   "svc",
+		"resource":      slog.Options{"deployment.environment": "test"},
+		"name":          "api",
+		"flushInterval": time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hl := lg.(*slog.HandlerLogger)
+	hl.Infow("started", "port", 8080, "tls", true)
+	hl.Errorw("failed", "err", errors.New("boom"), "ratio", 0.5)
+	slog.Leveled(hl).Logw(notice, "noticed", "tags", []interface{}{"a", "b"})
+	hl.WithName("db").Debug("query")
+	if err := hl.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) != 1 || len(c.requests[0].ResourceLogs) != 1 {
+		t.Fatalf("requests %+v, want single export request", c.requests)
+	}
+	rl := c.requests[0].ResourceLogs[0]
+	res := attrs(rl.Resource.Attributes)
+	if str(res["service.name"]) != `{"stringValue":"svc"}` || str(res["deployment.environment"]) != `{"stringValue":"test"}` {
+		t.Errorf("resource attributes %s", str(anyValue{KvlistValue: &kvlistValue{Values: rl.Resource.Attributes}}))
+	}
+
+	type record struct {
+		scope string
+		sev   int
+		text  string
+		body  string
+		attrs map[string]string
+	}
+	want := []record{
+		{"api", SeverityInfo, "INFO", "started", map[string]string{
+			"port": `{"intValue":"8080"}`, "tls": `{"boolValue":true}`}},
+		{"api", SeverityError, "ERROR", "failed", map[string]string{
+			"err": `{"stringValue":"boom"}`, "ratio": `{"doubleValue":0.5}`}},
+		{"api", SeverityInfo + 2, "OTLP-NOTICE", "noticed", map[string]string{
+			"tags": `{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}`}},
+		{"db", SeverityDebug, "DEBUG", "query", map[string]string{}},
+	}
+	var got []record
+	for _, sl := range rl.ScopeLogs {
+		for _, raw := range sl.LogRecords {
+			var lr logRecord
+			if err := json.Unmarshal(raw, &lr); err != nil {
+				t.Fatal(err)
+			}
+			if lr.TimeUnixNano == "" || lr.ObservedTimeUnixNano == "" {
+				t.Errorf("record %s without timestamps", raw)
+			}
+			a := make(map[string]string)
+			for k, v := range attrs(lr.Attributes) {
+				a[k] = str(v)
+			}
+			got = append(got, record{sl.Scope.Name, lr.SeverityNumber, lr.SeverityText, *lr.Body.StringValue, a})
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.scope != w.scope || g.sev != w.sev || g.text != w.text || g.body != w.body {
+			t.Errorf("record %d = %+v, want %+v", i, g, w)
+		}
+		if len(g.attrs) != len(w.attrs) {
+			t.Errorf("record %d attributes %v, want %v", i, g.attrs, w.attrs)
+		}
+		for k, v := range w.attrs {
+			if g.attrs[k] != v {
+				t.Errorf("record %d attribute %s = %s, want %s", i, k, g.attrs[k], v)
+			}
+		}
+	}
+}
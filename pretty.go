@@ -0,0 +1,222 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default column widths of PrettyEncoder
+const (
+	DefaultPrettyNameWidth    = 10
+	DefaultPrettyMessageWidth = 40
+	DefaultPrettyLineWidth    = 120
+)
+
+// dim style used by PrettyEncoder when theme does not set color
+var dimColor = MustParseColor("dim")
+
+// PrettyEncoder writes human friendly entry for local development:
+//
+//	1.204s INFOO [http] request done              status=200 path=/
+//
+// Time, level, logger name and message are aligned in columns. Fields are written dimmed
+// on the same line, or each on indented line when the line is too long. Multi-line message,
+// multi-line values and error stack traces (`%+v`) are indented under the header.
+type PrettyEncoder struct {
+	// TimestampFormat of time column (see FormatTimestamp),
+	// empty means seconds elapsed since process start
+	TimestampFormat string
+	// UTC writes timestamp in UTC instead of local time
+	UTC          bool
+	DisableColor bool
+	// Theme of colored output, nil means DefaultTheme
+	Theme *Theme
+	// ColorDepth supported by output, see DetectColorDepth
+	ColorDepth ColorDepth
+	// MessageWidth is minimum width of message column when fields follow it
+	MessageWidth int
+	// LineWidth is maximum width of line with inline fields
+	LineWidth int
+	// NameWidth is width of logger name column, longer names are truncated.
+	// Zero means DefaultPrettyNameWidth, negative writes names without alignment.
+	NameWidth int
+}
+
+func (pe *PrettyEncoder) style(c Color, str string) string {
+	if pe.DisableColor {
+		return str
+	}
+	if c.IsZero() {
+		c = dimColor
+	}
+	return c.Wrap(str, pe.ColorDepth)
+}
+
+func (pe *PrettyEncoder) theme() *Theme {
+	if pe.Theme != nil {
+		return pe.Theme
+	}
+	return defaultTheme
+}
+
+// writeName writes logger name column and returns its width
+func (pe *PrettyEncoder) writeName(buf *bytes.Buffer, name string) int {
+	w := pe.NameWidth
+	if w == 0 {
+		w = DefaultPrettyNameWidth
+	}
+	if w < 0 {
+		if name == "" {
+			return 0
+		}
+		w = utf8.RuneCountInString(name)
+	}
+	if name == "" {
+		writePadded(buf, "", w+3)
+		return w + 3
+	}
+	buf.WriteByte('[')
+	writePadded(buf, truncateRunes(name, w), w)
+	buf.WriteString("] ")
+	return w + 3
+}
+
+// Encode entry as one or more lines
+func (pe *PrettyEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	theme := pe.theme()
+
+	// header columns, indent is width of the header
+	ts, _, ok := e.FormatTimestamp(pe.TimestampFormat, pe.UTC)
+	switch strings.ToLower(pe.TimestampFormat) {
+	case "", TimestampElapsed:
+		ts = fmt.Sprintf("%9.3fs", e.Elapsed().Seconds())
+	}
+	lv := LevelFixedString(e.Level)
+	indent := utf8.RuneCountInString(lv) + 1
+	if ok {
+		buf.WriteString(pe.style(theme.Timestamp, ts))
+		buf.WriteByte(' ')
+		indent += utf8.RuneCountInString(ts) + 1
+	}
+	if pe.DisableColor {
+		buf.WriteString(lv)
+	} else {
+		buf.WriteString(theme.level(e.Level, pe.ColorDepth, lv))
+	}
+	buf.WriteByte(' ')
+	indent += pe.writeName(buf, e.Logger)
+	if e.Caller != nil {
+		caller := e.Caller.String()
+		buf.WriteString(caller)
+		buf.WriteByte(' ')
+		indent += utf8.RuneCountInString(caller) + 1
+	}
+
+	// fields with multiple lines (e.g. stack trace) are written as blocks
+	var inline []string
+	var blocks []Field
+	for _, f := range FlattenFields(e.Fields) {
+		if err, ok := f.Value.(error); ok {
+			if st := fmt.Sprintf("%+v", err); st != err.Error() && strings.Contains(st, "\n") {
+				blocks = append(blocks, Field{Key: f.Key, Value: st})
+				continue
+			}
+		}
+		str, _ := AsString(f.Value)
+		if strings.Contains(str, "\n") {
+			blocks = append(blocks, Field{Key: f.Key, Value: str})
+			continue
+		}
+		inline = append(inline, prettyField(f.Key, str))
+	}
+
+	msgWidth := pe.MessageWidth
+	if msgWidth <= 0 {
+		msgWidth = DefaultPrettyMessageWidth
+	}
+	lineWidth := pe.LineWidth
+	if lineWidth <= 0 {
+		lineWidth = DefaultPrettyLineWidth
+	}
+	lines := strings.Split(strings.TrimRight(e.Message, "\n"), "\n")
+	fieldsText := strings.Join(inline, " ")
+	sameLine := len(lines) == 1 &&
+		indent+maxInt(utf8.RuneCountInString(lines[0]), msgWidth)+1+utf8.RuneCountInString(fieldsText) <= lineWidth
+
+	// message
+	pad := strings.Repeat(" ", indent)
+	if sameLine && len(inline) > 0 {
+		writePadded(buf, lines[0], msgWidth)
+		buf.WriteByte(' ')
+		buf.WriteString(pe.style(theme.Value, fieldsText))
+		inline = nil
+	} else {
+		buf.WriteString(lines[0])
+	}
+	for _, line := range lines[1:] {
+		buf.WriteByte('\n')
+		buf.WriteString(pad)
+		buf.WriteString(line)
+	}
+
+	// fields, one per line
+	for _, f := range inline {
+		buf.WriteByte('\n')
+		buf.WriteString(pad)
+		buf.WriteString(pe.style(theme.Value, f))
+	}
+	for _, f := range blocks {
+		buf.WriteByte('\n')
+		buf.WriteString(pad)
+		buf.WriteString(pe.style(theme.Key, logfmtKey(f.Key)+":"))
+		for _, line := range strings.Split(strings.TrimRight(f.Value.(string), "\n"), "\n") {
+			buf.WriteByte('\n')
+			buf.WriteString(pad)
+			buf.WriteString("  ")
+			buf.WriteString(line)
+		}
+	}
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+func prettyField(key, val string) string {
+	var buf bytes.Buffer
+	writeLogfmt(&buf, key, val)
+	return buf.String()
+}
+
+func writePadded(buf *bytes.Buffer, s string, width int) {
+	buf.WriteString(s)
+	for n := utf8.RuneCountInString(s); n < width; n++ {
+		buf.WriteByte(' ')
+	}
+}
+
+// truncateRunes cuts s to n runes, last rune is replaced by ellipsis
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	i, count := 0, 0
+	for i = range s {
+		if count == n-1 {
+			break
+		}
+		count++
+	}
+	return s[:i] + "…"
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
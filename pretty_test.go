@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrettyEncoderNameWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		logger string
+		want   string
+	}{
+		{"default pads", 0, "http", "INFOO [http      ] hello\n"},
+		{"default without name", 0, "", "INFOO              hello\n"},
+		{"fixed width", 6, "db", "INFOO [db    ] hello\n"},
+		{"exact width", 4, "http", "INFOO [http] hello\n"},
+		{"long name truncated", 4, "scheduler", "INFOO [sch…] hello\n"},
+		{"truncated at rune boundary", 3, "日本語ログ", "INFOO [日本…] hello\n"},
+		{"unaligned", -1, "scheduler", "INFOO [scheduler] hello\n"},
+		{"unaligned without name", -1, "", "INFOO hello\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pe := &PrettyEncoder{TimestampFormat: TimestampNone, DisableColor: true, NameWidth: tt.width}
+			e := NewEntry(InfoLevel, "hello", nil)
+			e.Logger = tt.logger
+			var buf bytes.Buffer
+			if err := pe.Encode(&buf, e); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyEncoderNameColumnFixed(t *testing.T) {
+	// column does not grow after a long name was written
+	pe := &PrettyEncoder{TimestampFormat: TimestampNone, DisableColor: true, NameWidth: 4}
+	var want string
+	for i, logger := range []string{"db", "a-very-long-logger-name", "db"} {
+		e := NewEntry(InfoLevel, "hello", nil)
+		e.Logger = logger
+		var buf bytes.Buffer
+		if err := pe.Encode(&buf, e); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			want = buf.String()
+		} else if i == 2 && buf.String() != want {
+			t.Errorf("Encode() = %q after long name, want %q", buf.String(), want)
+		}
+	}
+
+	var buf bytes.Buffer
+	lg, err := NewStdLogger(&buf, InfoLevel, Options{"formatter": "pretty", "timestampFormat": "none",
+		"color": "never", "name": "api", "nameWidth": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("hello")
+	if got, want := buf.String(), "INFOO [api  ] hello\n"; got != want {
+		t.Errorf("stdlog pretty = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package redact
+
+import (
+	"regexp"
+)
+
+// Detector finds sensitive data inside string values.
+// Each match is replaced using Strategy, or the redactor strategy if nil.
+// Validate, if set, is called for each match to reduce false positives.
+type Detector struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool
+	Strategy Strategy
+}
+
+// Built-in detectors
+var (
+	// CreditCard detects 13-19 digit card numbers (optionally separated by space
+	// or dash) which pass Luhn check
+	CreditCard = Detector{
+		Name:     "credit_card",
+		Pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate: luhn,
+	}
+
+	// JWT detects JSON web tokens
+	JWT = Detector{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+
+	// Email detects email addresses
+	Email = Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+
+	// AWSAccessKey detects AWS access key IDs
+	AWSAccessKey = Detector{
+		Name:    "aws_access_key",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`),
+	}
+
+	// AWSSecretKey detects AWS secret access key assigned in text,
+	// e.g. `aws_secret_access_key=...`
+	AWSSecretKey = Detector{
+		Name:    "aws_secret_key",
+		Pattern: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?[=:]\s*["']?[A-Za-z0-9/+=]{40}\b`),
+	}
+)
+
+// DefaultDetectors used when detectors are not configured
+func DefaultDetectors() []Detector {
+	return []Detector{CreditCard, JWT, Email, AWSAccessKey, AWSSecretKey}
+}
+
+// luhn checks digits of card number, separators are ignored
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+func (d *Detector) replace(s string, def Strategy) string {
+	strategy := d.Strategy
+	if strategy == nil {
+		strategy = def
+	}
+	return d.Pattern.ReplaceAllStringFunc(s, func(m string) string {
+		if d.Validate != nil && !d.Validate(m) {
+			return m
+		}
+		return strategy(m)
+	})
+}
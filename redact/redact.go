@@ -0,0 +1,263 @@
+// Package redact removes secrets and personal data from entries before they are
+// written. Redactor is a slog.Hook, attach it using slog.NewHookedLogger, and a
+// slog.Redactor set by HandlerLogger.SetRedactor. Importing the package enables
+// redact options of the loggers.
+package redact
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ipsusila/slog"
+)
+
+// Redacted is implemented by types which must never be logged as is,
+// value returned by Redacted is written instead (see slog.Redacted).
+type Redacted = slog.Redacted
+
+// Rule redacts value of field whose key matches glob pattern (see path.Match).
+// Pattern is matched case insensitively against the whole key and the last
+// segment of dotted key. Strategy is optional.
+type Rule struct {
+	Key      string
+	Strategy Strategy
+}
+
+// DefaultRules used when rules are not configured
+func DefaultRules() []Rule {
+	return []Rule{
+		{Key: "*password*"},
+		{Key: "passwd"},
+		{Key: "*secret*"},
+		{Key: "*token*"},
+		{Key: "authorization"},
+		{Key: "*cookie*"},
+		{Key: "*api?key*"},
+		{Key: "*apikey*"},
+		{Key: "*private?key*"},
+		{Key: "credential*"},
+	}
+}
+
+// Config of redactor, nil rules and detectors use defaults
+type Config struct {
+	Rules     []Rule
+	Detectors []Detector
+	// Strategy used when rule or detector does not specify one,
+	// default to Mask(DefaultReplacement)
+	Strategy Strategy
+	// Messages applies detectors to messages
+	Messages bool
+	// Levels where redaction is applied, default to all levels
+	Levels []slog.Level
+}
+
+// maximum nesting of map and array values which are redacted
+const maxDepth = 8
+
+// Redactor hook redacts entry fields and optionally message
+type Redactor struct {
+	cfg Config
+}
+
+// New creates redactor
+func New(cfg Config) *Redactor {
+	if cfg.Rules == nil {
+		cfg.Rules = DefaultRules()
+	}
+	if cfg.Detectors == nil {
+		cfg.Detectors = DefaultDetectors()
+	}
+	if cfg.Strategy == nil {
+		cfg.Strategy = Mask(DefaultReplacement)
+	}
+	rules := make([]Rule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rule.Key = strings.ToLower(rule.Key)
+		rules[i] = rule
+	}
+	cfg.Rules = rules
+	return &Redactor{cfg: cfg}
+}
+
+func init() {
+	slog.SetRedactorFactory(func(rd slog.Redaction) (slog.Redactor, error) {
+		return FromRedaction(rd)
+	})
+}
+
+// FromRedaction creates redactor from redact options, keys and patterns
+// are added to the default rules and detectors
+func FromRedaction(rd slog.Redaction) (*Redactor, error) {
+	cfg := Config{
+		Rules:     DefaultRules(),
+		Detectors: DefaultDetectors(),
+		Messages:  rd.Messages,
+	}
+	for _, key := range rd.Keys {
+		if _, err := path.Match(key, ""); err != nil {
+			return nil, fmt.Errorf("redact key %q: %w", key, err)
+		}
+		cfg.Rules = append(cfg.Rules, Rule{Key: key})
+	}
+	for _, pattern := range rd.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+		}
+		cfg.Detectors = append(cfg.Detectors, Detector{Name: "pattern", Pattern: re})
+	}
+	return New(cfg), nil
+}
+
+// Levels where redaction is applied, empty means all levels
+func (r *Redactor) Levels() []slog.Level {
+	return r.cfg.Levels
+}
+
+// Fire redacts entry
+func (r *Redactor) Fire(e *slog.Entry) error {
+	if r.cfg.Messages {
+		e.Message = r.String(e.Message)
+	}
+	for i := range e.Fields {
+		e.Fields[i].Value = r.Field(e.Fields[i].Key, e.Fields[i].Value)
+	}
+	return nil
+}
+
+// Redact entry with level listed by Levels
+func (r *Redactor) Redact(e *slog.Entry) {
+	if len(r.cfg.Levels) != 0 && !hasLevel(r.cfg.Levels, e.Level) {
+		return
+	}
+	r.Fire(e)
+}
+
+func hasLevel(levels []slog.Level, lv slog.Level) bool {
+	for _, l := range levels {
+		if l == lv {
+			return true
+		}
+	}
+	return false
+}
+
+// match return strategy of the first rule matching key
+func (r *Redactor) match(key string) (Strategy, bool) {
+	key = strings.ToLower(key)
+	last := key
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		last = key[i+1:]
+	}
+	for _, rule := range r.cfg.Rules {
+		ok, _ := path.Match(rule.Key, key)
+		if !ok && last != key {
+			ok, _ = path.Match(rule.Key, last)
+		}
+		if ok {
+			if rule.Strategy != nil {
+				return rule.Strategy, true
+			}
+			return r.cfg.Strategy, true
+		}
+	}
+	return nil, false
+}
+
+// Field return redacted value of field
+func (r *Redactor) Field(key string, val interface{}) interface{} {
+	return r.field(key, val, 0)
+}
+
+func (r *Redactor) field(key string, val interface{}, depth int) interface{} {
+	if strategy, ok := r.match(key); ok {
+		if val == nil {
+			return nil
+		}
+		if rv, ok := val.(Redacted); ok {
+			return rv.Redacted()
+		}
+		str, _ := slog.AsString(val)
+		return strategy(str)
+	}
+	return r.value(val, depth)
+}
+
+// String applies detectors to s
+func (r *Redactor) String(s string) string {
+	for i := range r.cfg.Detectors {
+		s = r.cfg.Detectors[i].replace(s, r.cfg.Strategy)
+	}
+	return s
+}
+
+func (r *Redactor) value(val interface{}, depth int) interface{} {
+	switch v := val.(type) {
+	case Redacted:
+		return v.Redacted()
+	case string:
+		return r.String(v)
+	case error:
+		if s := v.Error(); r.String(s) != s {
+			return r.String(s)
+		}
+	case map[string]interface{}:
+		if depth < maxDepth {
+			m := make(map[string]interface{}, len(v))
+			for k, item := range v {
+				m[k] = r.field(k, item, depth+1)
+			}
+			return m
+		}
+	case slog.Object:
+		if depth < maxDepth {
+			o := make(slog.Object, len(v))
+			for i, f := range v {
+				o[i] = slog.Field{Key: f.Key, Value: r.field(f.Key, f.Value, depth+1)}
+			}
+			return o
+		}
+	case []interface{}:
+		if depth < maxDepth {
+			arr := make([]interface{}, len(v))
+			for i, item := range v {
+				arr[i] = r.value(item, depth+1)
+			}
+			return arr
+		}
+	case []string:
+		arr := make([]string, len(v))
+		for i, item := range v {
+			arr[i] = r.String(item)
+		}
+		return arr
+	default:
+		// values formatted later (Stringer, struct, ...), detectors
+		// run on the formatted string
+		if val != nil && !isScalar(val) {
+			s, _ := slog.AsString(val)
+			if rs := r.String(s); rs != s {
+				return rs
+			}
+		}
+	}
+	return val
+}
+
+// isScalar reports whether val is bool or number without String method
+func isScalar(val interface{}) bool {
+	if _, ok := val.(fmt.Stringer); ok {
+		return false
+	}
+	switch reflect.TypeOf(val).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
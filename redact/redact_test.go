@@ -0,0 +1,171 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ipsusila/slog"
+	_ "github.com/ipsusila/slog/logrus"
+)
+
+type secret string
+
+func (secret) Redacted() interface{} { return "<secret>" }
+
+// stringer formatted later by encoders
+type contact struct{ email string }
+
+func (c contact) String() string { return "contact " + c.email }
+
+// redacted value which is also LogValuer and ObjectMarshaler
+type account struct{ id, password string }
+
+func (a *account) Redacted() interface{} { return "account " + a.id }
+func (a *account) LogValue() interface{} { return a.id + ":" + a.password }
+func (a *account) MarshalLogObject(enc slog.ObjectEncoder) error {
+	enc.AddField("password", a.password)
+	return nil
+}
+
+func TestField(t *testing.T) {
+	r := New(Config{
+		Rules:    append(DefaultRules(), Rule{Key: "ssn", Strategy: MaskKeep(4)}),
+		Strategy: Mask("***"),
+	})
+	tests := []struct {
+		name string
+		key  string
+		val  interface{}
+		want interface{}
+	}{
+		{"key rule", "password", "hunter2", "***"},
+		{"case insensitive glob", "X-Api-Key", "abc", "***"},
+		{"last segment of dotted key", "http.Authorization", "Bearer x", "***"},
+		{"rule strategy", "ssn", "123-45-6789", "*******6789"},
+		{"nil value", "token", nil, nil},
+		{"marker interface", "user", secret("bob"), "<secret>"},
+		{"email detector", "note", "mail bob@example.com now", "mail *** now"},
+		{"card passes luhn", "note", "card 4111 1111 1111 1111", "card ***"},
+		{"card fails luhn", "note", "id 4111 1111 1111 1112", "id 4111 1111 1111 1112"},
+		{"error value", "err", errors.New("token eyJa.eyJb.c rejected"), "token *** rejected"},
+		{"plain value", "n", 42, 42},
+		{"stringer detector", "owner", contact{"bob@example.com"}, "contact ***"},
+		{"stringer unchanged", "owner", contact{"bob"}, contact{"bob"}},
+		{"struct detector", "owner", struct{ Email string }{"bob@example.com"}, "{***}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Field(tt.key, tt.val); got != tt.want {
+				t.Errorf("Field(%q, %v) = %v, want %v", tt.key, tt.val, got, tt.want)
+			}
+		})
+	}
+
+	nested := r.Field("user", map[string]interface{}{"name": "bob", "password": "x"}).(map[string]interface{})
+	if nested["name"] != "bob" || nested["password"] != "***" {
+		t.Errorf("nested map %v", nested)
+	}
+}
+
+func TestRedactedBeforeResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		redactor bool
+	}{
+		{"with redactor", true},
+		{"without redactor", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			lg, err := slog.NewStdLogger(&buf, slog.InfoLevel, slog.Options{"formatter": "json", "timestampFormat": "none", "redact": tt.redactor})
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg.Infow("login", "account", &account{id: "a1", password: "hunter2"})
+			out := buf.String()
+			if strings.Contains(out, "hunter2") || !strings.Contains(out, `"account":"account a1"`) {
+				t.Errorf("got %s", out)
+			}
+		})
+	}
+}
+
+func TestRedactLevels(t *testing.T) {
+	r := New(Config{Levels: []slog.Level{slog.InfoLevel}, Messages: true})
+	tests := []struct {
+		lv   slog.Level
+		want string
+	}{
+		{slog.InfoLevel, DefaultReplacement},
+		{slog.DebugLevel, "bob@example.com"},
+	}
+	for _, tt := range tests {
+		e := slog.NewEntry(tt.lv, "bob@example.com", nil)
+		r.Redact(e)
+		if e.Message != tt.want {
+			t.Errorf("%v message %q, want %q", tt.lv, e.Message, tt.want)
+		}
+	}
+}
+
+func TestFromRedactionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rd   slog.Redaction
+	}{
+		{"bad key glob", slog.Redaction{Enabled: true, Keys: []string{"[ssn"}}},
+		{"bad pattern", slog.Redaction{Enabled: true, Patterns: []string{"(a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromRedaction(tt.rd); err == nil {
+				t.Error("invalid redaction accepted")
+			}
+		})
+	}
+}
+
+func TestRedactOptions(t *testing.T) {
+	op := slog.Options{
+		"redactKeys":     "ssn,pin",
+		"redactPatterns": []string{`ORD-\d+`},
+		"redactMessages": true,
+	}
+	tests := []struct {
+		name    string
+		logger  string
+		options slog.Options
+	}{
+		{"stdlog", "stdlog", slog.Options{"formatter": "json", "timestampFormat": "none"}},
+		{"logrus", "logrus", slog.Options{"formatter": "json", "disableTimestamp": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range op {
+				tt.options[k] = v
+			}
+			var buf bytes.Buffer
+			lg, err := slog.NewWithOptions(tt.logger, &buf, slog.InfoLevel, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg.Infow("order ORD-42 by bob@example.com", "ssn", "123", "pin", 42, "password", "x", "ref", "ORD-7", "n", 1)
+			out := buf.String()
+			for _, leaked := range []string{"ORD-", "bob@", "123", "42", `"x"`} {
+				if strings.Contains(out, leaked) {
+					t.Errorf("%s leaked: %s", leaked, out)
+				}
+			}
+			if !strings.Contains(out, `"n":1`) {
+				t.Errorf("unrelated field redacted: %s", out)
+			}
+		})
+	}
+
+	if _, err := slog.NewWithOptions("stdlog", &bytes.Buffer{}, slog.InfoLevel, slog.Options{"redactPatterns": "(a"}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
@@ -0,0 +1,71 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultReplacement written by default mask strategy
+const DefaultReplacement = "[REDACTED]"
+
+// Strategy return replacement of sensitive value
+type Strategy func(val string) string
+
+// Mask replaces value with fixed replacement
+func Mask(replacement string) Strategy {
+	return func(string) string {
+		return replacement
+	}
+}
+
+// MaskKeep replaces all but the last keep characters with '*',
+// e.g. for card number ************1111
+func MaskKeep(keep int) Strategy {
+	return func(val string) string {
+		n := utf8.RuneCountInString(val)
+		if n <= keep {
+			return val
+		}
+		var sb strings.Builder
+		i := 0
+		for _, r := range val {
+			if i < n-keep {
+				sb.WriteByte('*')
+			} else {
+				sb.WriteRune(r)
+			}
+			i++
+		}
+		return sb.String()
+	}
+}
+
+// Hash replaces value with salted SHA-256 HMAC, so equal values can still
+// be correlated without revealing them
+func Hash(salt []byte) Strategy {
+	return func(val string) string {
+		mac := hmac.New(sha256.New, salt)
+		mac.Write([]byte(val))
+		return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+	}
+}
+
+// Truncate keeps the first n characters
+func Truncate(n int) Strategy {
+	return func(val string) string {
+		if utf8.RuneCountInString(val) <= n {
+			return val
+		}
+		i := 0
+		for pos := range val {
+			if i == n {
+				return val[:pos] + "..."
+			}
+			i++
+		}
+		return val
+	}
+}
@@ -0,0 +1,113 @@
+package slog
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Redaction option names
+const (
+	fieldRedact         = "redact"
+	fieldRedactKeys     = "redactKeys"
+	fieldRedactPatterns = "redactPatterns"
+	fieldRedactMessages = "redactMessages"
+)
+
+// Redaction options accepted by loggers
+var (
+	OptionRedact = OptionSpec{Name: fieldRedact, Type: BoolOption, Default: false,
+		Description: "redact secrets and personal data using default rules and detectors"}
+	OptionRedactKeys = OptionSpec{Name: fieldRedactKeys, Type: AnyOption,
+		Description: "additional glob patterns of redacted field keys, list or comma separated string"}
+	OptionRedactPatterns = OptionSpec{Name: fieldRedactPatterns, Type: AnyOption,
+		Description: "additional regular expressions of redacted values, list or single string"}
+	OptionRedactMessages = OptionSpec{Name: fieldRedactMessages, Type: BoolOption, Default: false,
+		Description: "apply value detectors to messages"}
+)
+
+// Redactor removes sensitive data from entry before it is handled
+type Redactor interface {
+	Redact(e *Entry)
+}
+
+// Redaction configured by redact options.
+// Keys and Patterns extend the default rules and detectors.
+type Redaction struct {
+	Enabled  bool
+	Keys     []string
+	Patterns []string
+	Messages bool
+}
+
+// RedactorFactory creates redactor from options, set by package redact
+type RedactorFactory func(rd Redaction) (Redactor, error)
+
+var (
+	redactorFactoryMu sync.RWMutex
+	redactorFactory   RedactorFactory
+)
+
+// SetRedactorFactory set function used by Options.GetRedactor.
+// Importing package redact sets the factory.
+func SetRedactorFactory(fn RedactorFactory) {
+	redactorFactoryMu.Lock()
+	defer redactorFactoryMu.Unlock()
+	redactorFactory = fn
+}
+
+// GetRedaction return redaction from redact, redactKeys, redactPatterns
+// and redactMessages options. Redaction is enabled by redact option
+// or when keys or patterns are given.
+func (op Options) GetRedaction() Redaction {
+	rd := Redaction{
+		Keys:     toStrings(op[fieldRedactKeys], true),
+		Patterns: toStrings(op[fieldRedactPatterns], false),
+		Messages: op.GetBool(fieldRedactMessages, false),
+	}
+	rd.Enabled = op.GetBool(fieldRedact, false) || len(rd.Keys) != 0 || len(rd.Patterns) != 0
+	return rd
+}
+
+// GetRedactor return redactor configured by redact options, or nil if redaction is not enabled
+func (op Options) GetRedactor() (Redactor, error) {
+	rd := op.GetRedaction()
+	if !rd.Enabled {
+		return nil, nil
+	}
+	redactorFactoryMu.RLock()
+	fn := redactorFactory
+	redactorFactoryMu.RUnlock()
+	if fn == nil {
+		return nil, errors.New("redact options require importing package github.com/ipsusila/slog/redact")
+	}
+	return fn(rd)
+}
+
+// toStrings converts list or string, split by comma if split is set.
+// Empty items are ignored.
+func toStrings(val interface{}, split bool) []string {
+	var items []string
+	switch v := val.(type) {
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := toString(item); ok {
+				items = append(items, s)
+			}
+		}
+	case string:
+		items = []string{v}
+		if split {
+			items = strings.Split(v, ",")
+		}
+	}
+	var strs []string
+	for _, s := range items {
+		if s = strings.TrimSpace(s); s != "" {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,78 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetRedaction(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Options
+		want Redaction
+	}{
+		{"none", Options{}, Redaction{}},
+		{"switch", Options{"redact": true, "redactMessages": true}, Redaction{Enabled: true, Messages: true}},
+		{"comma separated keys", Options{"redactKeys": "ssn, *pin* ,"},
+			Redaction{Enabled: true, Keys: []string{"ssn", "*pin*"}}},
+		{"key list", Options{"redactKeys": []interface{}{"ssn", "pin"}},
+			Redaction{Enabled: true, Keys: []string{"ssn", "pin"}}},
+		{"single pattern with comma", Options{"redactPatterns": `\d{3,4}`},
+			Redaction{Enabled: true, Patterns: []string{`\d{3,4}`}}},
+		{"disabled switch ignores messages", Options{"redact": false, "redactMessages": true},
+			Redaction{Messages: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op.GetRedaction(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRedaction() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRedactorWithoutFactory(t *testing.T) {
+	if r, err := (Options{}).GetRedactor(); r != nil || err != nil {
+		t.Errorf("GetRedactor() = %v, %v, want nil redactor", r, err)
+	}
+	if _, err := (Options{"redact": true}).GetRedactor(); err == nil || !strings.Contains(err.Error(), "redact") {
+		t.Errorf("GetRedactor() error = %v, want import hint", err)
+	}
+	if _, err := NewStdLogger(nil, InfoLevel, Options{"redactKeys": "ssn"}); err == nil {
+		t.Error("logger created with redact options but without redactor")
+	}
+}
+
+// keyRedactor masks value of fields with the key
+type keyRedactor string
+
+func (k keyRedactor) Redact(e *Entry) {
+	for i := range e.Fields {
+		if e.Fields[i].Key == string(k) {
+			e.Fields[i].Value = "***"
+		}
+	}
+}
+
+func TestHandlerLoggerRedactor(t *testing.T) {
+	rec := &entryRecorder{}
+	hl := NewHandlerLogger(rec, InfoLevel)
+	hl.SetRedactor(keyRedactor("token"))
+	hl.SetLimits(Limits{MaxValue: 2})
+	hl.SetFieldPolicy(FieldPolicy{Duplicates: FirstWins})
+
+	// redactor sees fields after duplicate policy and before limits
+	hl.WithName("api").Infow("login", "token", "secret-value", "token", "second", "user", "bob")
+	e := rec.entry()
+	want := Fields{{Key: "token", Value: TruncateString("***", 2)}, {Key: "user", Value: TruncateString("bob", 2)}}
+	if !reflect.DeepEqual(e.Fields, want) {
+		t.Errorf("fields %v, want %v", e.Fields, want)
+	}
+
+	hl.SetRedactor(nil)
+	hl.Infow("login", "token", "ab")
+	if e := rec.entry(); e.Fields[0].Value != "ab" {
+		t.Errorf("token %v after redactor removed", e.Fields[0].Value)
+	}
+}
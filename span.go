@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// SpanContext identifies span of a distributed trace, IDs are lowercase hex strings
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// SpanExtractor return span of the context, e.g. bridge to tracing SDK
+type SpanExtractor func(ctx context.Context) (SpanContext, bool)
+
+type spanExtractorHolder struct {
+	fn SpanExtractor
+}
+
+type spanKey struct{}
+
+var spanExtractor atomic.Value
+
+func init() {
+	spanExtractor.Store(spanExtractorHolder{})
+}
+
+// ContextWithSpan return context carrying the span
+func ContextWithSpan(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanKey{}, sc)
+}
+
+// SetSpanExtractor set function used when span is not stored by ContextWithSpan,
+// nil removes the extractor.
+func SetSpanExtractor(fn SpanExtractor) {
+	spanExtractor.Store(spanExtractorHolder{fn: fn})
+}
+
+// SpanFromContext return span stored in context or by span extractor
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	if sc, ok := ctx.Value(spanKey{}).(SpanContext); ok {
+		return sc, true
+	}
+	if fn := spanExtractor.Load().(spanExtractorHolder).fn; fn != nil {
+		return fn(ctx)
+	}
+	return SpanContext{}, false
+}
@@ -4,11 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
-	"sync"
-	"time"
-
-	"github.com/fatih/color"
+	"strings"
 )
 
 // Name of the standard logger
@@ -18,30 +14,56 @@ const (
 	defaultTimestampFormat = "2006/01/02 15:04:05 MST"
 	fieldTimestampFormat   = "timestampFormat"
 	fieldDisableColor      = "disableColor"
+	fieldFormatter         = "formatter"
+	fieldName              = "name"
+	fieldReportCaller      = "reportCaller"
+	fieldNameWidth         = "nameWidth"
 )
 
 type stdLoggerConstructor struct{}
 
-// color mapper
-var colorMapper = map[Level]func(string, ...interface{}) string{
-	PanicLevel: color.HiRedString,
-	FatalLevel: color.HiMagentaString,
-	ErrorLevel: color.RedString,
-	WarnLevel:  color.YellowString,
-	InfoLevel:  color.GreenString,
-	DebugLevel: color.BlueString,
-	TraceLevel: color.CyanString,
-}
-
-// logger without output, except for panic
-type stdLogger struct {
-	LevelLoggerBase
-	mu           sync.Mutex
-	out          io.Writer
-	buf          bytes.Buffer
-	prefixes     map[Level]string
-	tsFormat     string
-	disableColor bool
+// options supported by standard logger
+var stdLoggerSchema = Schema{
+	{Name: fieldTimestampFormat, Type: StringOption, Default: defaultTimestampFormat,
+		Description: "timestamp layout format, or none, epoch, epoch_ms, epoch_ns, rfc3339nano or elapsed"},
+	{Name: fieldTimestampUTC, Type: BoolOption, Default: false, Description: "write timestamp in UTC instead of local time"},
+	{Name: fieldDisableTimestamp, Type: BoolOption, Default: false, Description: "do not write timestamp"},
+	{Name: fieldDisableColor, Type: BoolOption, Default: false, Description: "disable color in log"},
+	{Name: fieldColor, Type: StringOption, Default: "auto", Description: "color output (text, pretty), auto detects terminal",
+		Values: []string{"auto", "always", "never"}},
+	{Name: fieldTheme, Type: OptionsOption, Description: "colors of levels, key, value and timestamp (text, pretty)"},
+	{Name: fieldFormatter, Type: StringOption, Default: "text", Description: "output format",
+		Values: []string{"text", "pretty", "json", "logfmt", "ecs", "gcp"}},
+	{Name: fieldName, Type: StringOption, Default: "", Description: "logger name written in each entry"},
+	{Name: fieldReportCaller, Type: BoolOption, Default: false, Description: "add caller file and line to each entry"},
+	{Name: fieldNameWidth, Type: IntOption, Default: DefaultPrettyNameWidth,
+		Description: "width of logger name column (pretty), longer names are truncated, negative disables alignment"},
+	OptionLevelMode,
+	OptionDuplicateKeys,
+	OptionSortFields,
+	OptionRedact,
+	OptionRedactKeys,
+	OptionRedactPatterns,
+	OptionRedactMessages,
+	OptionMaxMessageLength,
+	OptionMaxValueLength,
+	OptionMaxFields,
+	OptionMaxElements,
+	OptionMaxEntryBytes,
+}
+
+// TextEncoder writes entry in standard logger format, i.e.
+// LEVEL [timestamp] message<TAB>key=value...
+type TextEncoder struct {
+	// TimestampFormat, see FormatTimestamp
+	TimestampFormat string
+	// UTC writes timestamp in UTC instead of local time
+	UTC          bool
+	DisableColor bool
+	// Theme of colored output, nil means DefaultTheme
+	Theme *Theme
+	// ColorDepth supported by output, see DetectColorDepth
+	ColorDepth ColorDepth
 }
 
 func init() {
@@ -54,305 +76,190 @@ func (c *stdLoggerConstructor) New(w io.Writer, l Level) (Logger, error) {
 
 // create logger with options
 func (c *stdLoggerConstructor) NewWithOptions(w io.Writer, l Level, op Options) (Logger, error) {
-	return NewStdLogger(w, l, op)
+	return newStdLogger(w, l, op)
+}
+
+// Schema return options supported by standard logger
+func (c *stdLoggerConstructor) Schema() Schema {
+	return stdLoggerSchema
 }
 
 // NewStdLogger creates new logger with given parameters
 func NewStdLogger(w io.Writer, l Level, op Options) (Logger, error) {
-	bl := NewLevelLoggerBase(l)
-	sl := stdLogger{
-		LevelLoggerBase: *bl,
-		out:             w,
-		prefixes:        make(map[Level]string),
-		tsFormat:        defaultTimestampFormat,
-		disableColor:    false,
+	if err := stdLoggerSchema.Validate(op); err != nil {
+		return nil, err
 	}
+	return newStdLogger(w, l, op)
+}
 
-	// customized options
-	if len(op) != 0 {
-		sl.tsFormat = op.GetString(fieldTimestampFormat, defaultTimestampFormat)
-		sl.disableColor = op.GetBool(fieldDisableColor, false)
+func newStdLogger(w io.Writer, l Level, op Options) (*HandlerLogger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
 	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckEncoderOptions(op); err != nil {
+		return nil, err
+	}
+	enc := NewEncoder(op)
+	theme, err := ThemeFromOptions(op.GetOptions(fieldTheme))
+	if err != nil {
+		return nil, err
+	}
+	disableColor := op.GetBool(fieldDisableColor, false)
+	switch strings.ToLower(op.GetString(fieldColor, "auto")) {
+	case "auto":
+		disableColor = disableColor || !ColorEnabled(w)
+	case "never":
+		disableColor = true
+	}
+	switch ce := enc.(type) {
+	case *TextEncoder:
+		ce.Theme, ce.DisableColor, ce.ColorDepth = theme, disableColor, DetectColorDepth()
+	case *PrettyEncoder:
+		ce.Theme, ce.DisableColor, ce.ColorDepth = theme, disableColor, DetectColorDepth()
+	}
+	sl := NewHandlerLogger(NewWriterHandler(w, LimitEncoder(enc, op.GetInt(fieldMaxEntryBytes, 0))), 0)
 
-	// create logger for each level
-	all := Levels()
-	for _, lv := range all {
-		prefix := LevelFixedString(lv)
-		if prefix != "" {
-			prefix += " "
-		}
-
-		if !sl.disableColor {
-			if fn, ok := colorMapper[lv]; ok {
-				prefix = fn(prefix)
+	// customized options
+	if len(op) != 0 {
+		sl.SetName(op.GetString(fieldName, ""))
+		sl.SetReportCaller(op.GetBool(fieldReportCaller, false))
+		sl.SetFieldPolicy(policy)
+		sl.SetRedactor(redactor)
+		sl.SetLimits(op.GetLimits())
+		sl.SetLevelMode(op.GetLevelMode(fieldLevelMode, ThresholdMode))
+	}
+	sl.SetLevel(l)
+
+	return sl, nil
+}
+
+// CheckEncoderOptions return error when options can not be applied to encoder selected by `formatter`,
+// i.e. timestamp options of ecs or gcp, which always write UTC timestamp in format required by the schema
+func CheckEncoderOptions(op Options) error {
+	switch formatter := strings.ToLower(op.GetString(fieldFormatter, "text")); formatter {
+	case "ecs", "gcp":
+		for _, key := range []string{fieldTimestampFormat, fieldTimestampUTC, fieldDisableTimestamp} {
+			if _, ok := op[key]; ok {
+				return fmt.Errorf("option %q is not supported by %s formatter", key, formatter)
 			}
 		}
-		sl.prefixes[lv] = prefix
 	}
-
-	return &sl, nil
+	return nil
 }
 
-func (sl *stdLogger) writeHeader(prefix string) {
-	sl.buf.WriteString(prefix)
-	sl.buf.WriteRune('[')
-	sl.buf.WriteString(time.Now().Format(sl.tsFormat))
-	sl.buf.WriteString("] ")
-}
-
-func (sl *stdLogger) outputFields(lv Level, msg string, keyVals []interface{}, seps ...rune) {
-	sl.mu.Lock()
-	defer sl.mu.Unlock()
-
-	sep := '='
-	if len(seps) > 0 {
-		sep = seps[0]
-	}
-
-	prefix, ok := sl.prefixes[lv]
-	if !ok {
-		prefix = "OTHER"
-	}
-	sl.writeHeader(prefix)
-	sl.buf.WriteString(msg)
-	sl.buf.WriteRune('\t')
-
-	// write fields
-	n := len(keyVals)
-	if n > 0 {
-		// number of pair
-		nkv := (n + 1) / 2
-
-		// number of args is odd
-		if n%2 != 0 {
-			n--
+// NewEncoder creates encoder selected by `formatter` option (text, pretty, json, logfmt, ecs or gcp)
+func NewEncoder(op Options) Encoder {
+	switch strings.ToLower(op.GetString(fieldFormatter, "text")) {
+	case "json":
+		return &JSONEncoder{
+			TimestampFormat: timestampFormat(op, ""),
+			UTC:             op.GetBool(fieldTimestampUTC, false),
 		}
-
-		// format field and values
-		j := 0
-		for i := 0; i < n; i += 2 {
-			field, _ := AsString(keyVals[i])
-			if !sl.disableColor {
-				if fn, ok := colorMapper[lv]; ok {
-					field = fn(field)
-				}
-			}
-			sl.buf.WriteString(field)
-			sl.buf.WriteRune(sep)
-			sl.buf.WriteString(AsStringQ(keyVals[i+1]))
-			sl.buf.WriteRune(' ')
-			j++
+	case "logfmt":
+		return &LogfmtEncoder{
+			TimestampFormat: timestampFormat(op, ""),
+			UTC:             op.GetBool(fieldTimestampUTC, false),
 		}
-
-		// number of args is odd
-		if n != len(keyVals) {
-			field := fmt.Sprintf("%s-%02d", UnknownFieldName, nkv)
-			if !sl.disableColor {
-				if fn, ok := colorMapper[lv]; ok {
-					field = fn(field)
-				}
-			}
-			sl.buf.WriteString(field)
-			sl.buf.WriteRune(sep)
-			sl.buf.WriteString(AsStringQ(keyVals[n]))
+	case "ecs":
+		return &ECSEncoder{}
+	case "gcp":
+		return NewCloudLoggingEncoder()
+	case "pretty":
+		return &PrettyEncoder{
+			TimestampFormat: timestampFormat(op, ""),
+			UTC:             op.GetBool(fieldTimestampUTC, false),
+			DisableColor:    op.GetBool(fieldDisableColor, false),
+			NameWidth:       op.GetInt(fieldNameWidth, DefaultPrettyNameWidth),
+		}
+	default:
+		return &TextEncoder{
+			TimestampFormat: timestampFormat(op, defaultTimestampFormat),
+			UTC:             op.GetBool(fieldTimestampUTC, false),
+			DisableColor:    op.GetBool(fieldDisableColor, false),
 		}
 	}
-	// write LF
-	n = sl.buf.Len()
-	if n == 0 || sl.buf.Bytes()[n-1] != '\n' {
-		sl.buf.WriteRune('\n')
-	}
-
-	// copy content
-	io.Copy(sl.out, &sl.buf)
 }
 
-func (sl *stdLogger) output(lv Level, str string) {
-	sl.mu.Lock()
-	defer sl.mu.Unlock()
+// theme used when TextEncoder.Theme is not set
+var defaultTheme = DefaultTheme()
 
-	prefix, ok := sl.prefixes[lv]
-	if !ok {
-		prefix = "OTHER"
+func (te *TextEncoder) theme() *Theme {
+	if te.Theme != nil {
+		return te.Theme
 	}
-	sl.writeHeader(prefix)
-	sl.buf.WriteString(str)
-
-	// write LF
-	n := sl.buf.Len()
-	if n == 0 || sl.buf.Bytes()[n-1] != '\n' {
-		sl.buf.WriteRune('\n')
-	}
-
-	io.Copy(sl.out, &sl.buf)
+	return defaultTheme
 }
 
-func (sl *stdLogger) Trace(args ...interface{}) {
-	if sl.HasLevel(TraceLevel) {
-		sl.output(TraceLevel, fmt.Sprint(args...))
-	}
-}
-func (sl *stdLogger) Debug(args ...interface{}) {
-	if sl.HasLevel(DebugLevel) {
-		sl.output(DebugLevel, fmt.Sprint(args...))
-	}
-}
-func (sl *stdLogger) Print(args ...interface{}) {
-	sl.Info(args...)
-}
-func (sl *stdLogger) Info(args ...interface{}) {
-	if sl.HasLevel(InfoLevel) {
-		sl.output(InfoLevel, fmt.Sprint(args...))
-	}
-}
-func (sl *stdLogger) Warn(args ...interface{}) {
-	if sl.HasLevel(WarnLevel) {
-		sl.output(WarnLevel, fmt.Sprint(args...))
-	}
-}
-func (sl *stdLogger) Error(args ...interface{}) {
-	if sl.HasLevel(ErrorLevel) {
-		sl.output(ErrorLevel, fmt.Sprint(args...))
+func (te *TextEncoder) colored(lv Level, str string) string {
+	if te.DisableColor {
+		return str
 	}
+	return te.theme().level(lv, te.ColorDepth, str)
 }
-func (sl *stdLogger) Fatal(args ...interface{}) {
-	if sl.HasLevel(FatalLevel) {
-		sl.output(FatalLevel, fmt.Sprint(args...))
-	}
-	os.Exit(1)
 
-}
-func (sl *stdLogger) Panic(args ...interface{}) {
-	s := fmt.Sprint(args...)
-	if sl.HasLevel(PanicLevel) {
-		sl.output(PanicLevel, s)
+// key uses level color unless theme sets key color
+func (te *TextEncoder) key(lv Level, key string) string {
+	if c := te.theme().Key; !te.DisableColor && !c.IsZero() {
+		return c.Wrap(key, te.ColorDepth)
 	}
-	panic(s)
+	return te.colored(lv, key)
 }
 
-func (sl *stdLogger) Traceln(args ...interface{}) {
-	if sl.HasLevel(TraceLevel) {
-		sl.output(TraceLevel, fmt.Sprintln(args...))
-	}
-}
-func (sl *stdLogger) Debugln(args ...interface{}) {
-	if sl.HasLevel(DebugLevel) {
-		sl.output(DebugLevel, fmt.Sprintln(args...))
-	}
-}
-func (sl *stdLogger) Println(args ...interface{}) {
-	sl.Infoln(args...)
-}
-func (sl *stdLogger) Infoln(args ...interface{}) {
-	if sl.HasLevel(InfoLevel) {
-		sl.output(InfoLevel, fmt.Sprintln(args...))
-	}
-}
-func (sl *stdLogger) Warnln(args ...interface{}) {
-	if sl.HasLevel(WarnLevel) {
-		sl.output(WarnLevel, fmt.Sprintln(args...))
-	}
-}
-func (sl *stdLogger) Errorln(args ...interface{}) {
-	if sl.HasLevel(ErrorLevel) {
-		sl.output(ErrorLevel, fmt.Sprintln(args...))
+func (te *TextEncoder) styled(c Color, str string) string {
+	if te.DisableColor {
+		return str
 	}
-}
-func (sl *stdLogger) Fatalln(args ...interface{}) {
-	if sl.HasLevel(FatalLevel) {
-		sl.output(FatalLevel, fmt.Sprintln(args...))
-	}
-	os.Exit(1)
-}
-func (sl *stdLogger) Panicln(args ...interface{}) {
-	s := fmt.Sprintln(args...)
-	if sl.HasLevel(PanicLevel) {
-		sl.output(PanicLevel, s)
-	}
-	panic(s)
+	return c.Wrap(str, te.ColorDepth)
 }
 
-func (sl *stdLogger) Tracef(format string, args ...interface{}) {
-	if sl.HasLevel(TraceLevel) {
-		sl.output(TraceLevel, fmt.Sprintf(format, args...))
-	}
-}
-func (sl *stdLogger) Debugf(format string, args ...interface{}) {
-	if sl.HasLevel(DebugLevel) {
-		sl.output(DebugLevel, fmt.Sprintf(format, args...))
+// Encode entry as text line
+func (te *TextEncoder) Encode(buf *bytes.Buffer, e *Entry) error {
+	tsFormat := te.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = defaultTimestampFormat
 	}
-}
-func (sl *stdLogger) Printf(format string, args ...interface{}) {
-	sl.Infof(format, args...)
-}
-func (sl *stdLogger) Infof(format string, args ...interface{}) {
-	if sl.HasLevel(InfoLevel) {
-		sl.output(InfoLevel, fmt.Sprintf(format, args...))
-	}
-}
-func (sl *stdLogger) Warnf(format string, args ...interface{}) {
-	if sl.HasLevel(WarnLevel) {
-		sl.output(WarnLevel, fmt.Sprintf(format, args...))
-	}
-}
-func (sl *stdLogger) Errorf(format string, args ...interface{}) {
-	if sl.HasLevel(ErrorLevel) {
-		sl.output(ErrorLevel, fmt.Sprintf(format, args...))
-	}
-}
-func (sl *stdLogger) Fatalf(format string, args ...interface{}) {
-	if sl.HasLevel(FatalLevel) {
-		sl.output(FatalLevel, fmt.Sprintf(format, args...))
-	}
-	os.Exit(1)
-}
-func (sl *stdLogger) Panicf(format string, args ...interface{}) {
-	s := fmt.Sprintf(format, args...)
-	if sl.HasLevel(PanicLevel) {
-		sl.output(PanicLevel, s)
-	}
-	panic(s)
-}
 
-// with fields
-
-func (sl *stdLogger) Tracew(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(TraceLevel) {
-		sl.outputFields(TraceLevel, msg, keyVals)
-	}
-}
-func (sl *stdLogger) Debugw(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(DebugLevel) {
-		sl.outputFields(DebugLevel, msg, keyVals)
+	// header
+	buf.WriteString(te.colored(e.Level, LevelFixedString(e.Level)+" "))
+	if ts, _, ok := e.FormatTimestamp(tsFormat, te.UTC); ok {
+		buf.WriteRune('[')
+		buf.WriteString(te.styled(te.theme().Timestamp, ts))
+		buf.WriteString("] ")
 	}
-}
-func (sl *stdLogger) Printw(msg string, keyVals ...interface{}) {
-	sl.Infow(msg, keyVals...)
-}
-func (sl *stdLogger) Infow(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(InfoLevel) {
-		sl.outputFields(InfoLevel, msg, keyVals)
+	if e.Logger != "" {
+		buf.WriteRune('[')
+		buf.WriteString(e.Logger)
+		buf.WriteString("] ")
 	}
-}
-func (sl *stdLogger) Warnw(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(WarnLevel) {
-		sl.outputFields(WarnLevel, msg, keyVals)
+	if e.Caller != nil {
+		buf.WriteString(e.Caller.String())
+		buf.WriteRune(' ')
 	}
-}
-func (sl *stdLogger) Errorw(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(ErrorLevel) {
-		sl.outputFields(ErrorLevel, msg, keyVals)
-	}
-}
-func (sl *stdLogger) Fatalw(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(FatalLevel) {
-		sl.outputFields(FatalLevel, msg, keyVals)
+	buf.WriteString(e.Message)
+
+	// write fields
+	if len(e.Fields) > 0 {
+		buf.WriteRune('\t')
+		for i, f := range FlattenFields(e.Fields) {
+			if i > 0 {
+				buf.WriteRune(' ')
+			}
+			buf.WriteString(te.key(e.Level, f.Key))
+			buf.WriteRune('=')
+			buf.WriteString(te.styled(te.theme().Value, AsStringQ(f.Value)))
+		}
 	}
-	os.Exit(1)
-}
-func (sl *stdLogger) Panicw(msg string, keyVals ...interface{}) {
-	if sl.HasLevel(PanicLevel) {
-		sl.outputFields(PanicLevel, msg, keyVals)
+
+	// write LF
+	n := buf.Len()
+	if n == 0 || buf.Bytes()[n-1] != '\n' {
+		buf.WriteRune('\n')
 	}
-	panic(SimpleFormatter(msg, keyVals, "="))
+
+	return nil
 }
@@ -0,0 +1,155 @@
+package syslog
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Framing of message in stream transport
+type Framing int
+
+// Supported framing
+const (
+	// DefaultFraming uses octet counting for tcp and null terminator for unix stream
+	DefaultFraming Framing = iota
+	OctetCounting
+	NewlineFraming
+	NullFraming
+	NoFraming
+)
+
+var framingStrMap = map[string]Framing{
+	"default": DefaultFraming,
+	"octet":   OctetCounting,
+	"newline": NewlineFraming,
+	"null":    NullFraming,
+	"none":    NoFraming,
+}
+
+// local syslog socket paths
+var localPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Conn writes syslog messages over network or unix socket.
+// Connection is re-established once when write fails.
+type Conn struct {
+	mu      sync.Mutex
+	network string
+	address string
+	framing Framing
+	timeout time.Duration
+	conn    net.Conn
+	frame   []byte
+}
+
+// Dial connects to syslog server.
+// Empty network connects to local syslog socket using unixgram or unix stream.
+func Dial(network, address string, framing Framing) (*Conn, error) {
+	c := &Conn{
+		network: network,
+		address: address,
+		framing: framing,
+		timeout: 5 * time.Second,
+	}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Conn) connect() error {
+	if c.network != "" {
+		conn, err := net.DialTimeout(c.network, c.address, c.timeout)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+		return nil
+	}
+
+	// local syslog
+	paths := localPaths
+	if c.address != "" {
+		paths = []string{c.address}
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range paths {
+			conn, err := net.DialTimeout(network, path, c.timeout)
+			if err == nil {
+				c.conn = conn
+				return nil
+			}
+		}
+	}
+	return errors.New("syslog: unable to connect to local syslog")
+}
+
+// effective framing for current connection
+func (c *Conn) effectiveFraming() Framing {
+	if c.framing != DefaultFraming {
+		return c.framing
+	}
+	switch c.conn.LocalAddr().Network() {
+	case "tcp", "tcp4", "tcp6":
+		return OctetCounting
+	case "unix":
+		return NullFraming
+	}
+	return NoFraming
+}
+
+func (c *Conn) write(p []byte) error {
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	// datagram or framed stream message
+	msg := p
+	switch c.effectiveFraming() {
+	case OctetCounting:
+		msg = strconv.AppendInt(c.frame[:0], int64(len(p)), 10)
+		msg = append(append(msg, ' '), p...)
+		c.frame = msg
+	case NewlineFraming:
+		msg = append(append(c.frame[:0], p...), '\n')
+		c.frame = msg
+	case NullFraming:
+		msg = append(append(c.frame[:0], p...), 0)
+		c.frame = msg
+	}
+	if _, err := c.conn.Write(msg); err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Write single syslog message, reconnect and retry once on error
+func (c *Conn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.write(p); err != nil {
+		if err = c.write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close the connection
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,259 @@
+package syslog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+// Syslog severity
+const (
+	SevEmergency = iota
+	SevAlert
+	SevCritical
+	SevError
+	SevWarning
+	SevNotice
+	SevInfo
+	SevDebug
+)
+
+// Facility of syslog message
+type Facility int
+
+// Syslog facilities
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	Lpr
+	News
+	Uucp
+	Cron
+	AuthPriv
+	Ftp
+	Local0 Facility = iota + 4
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+var facilityStrMap = map[string]Facility{
+	"kern":     Kern,
+	"user":     User,
+	"mail":     Mail,
+	"daemon":   Daemon,
+	"auth":     Auth,
+	"syslog":   Syslog,
+	"lpr":      Lpr,
+	"news":     News,
+	"uucp":     Uucp,
+	"cron":     Cron,
+	"authpriv": AuthPriv,
+	"ftp":      Ftp,
+	"local0":   Local0,
+	"local1":   Local1,
+	"local2":   Local2,
+	"local3":   Local3,
+	"local4":   Local4,
+	"local5":   Local5,
+	"local6":   Local6,
+	"local7":   Local7,
+}
+
+// default structured data ID, 32473 is reserved for documentation (RFC 5612)
+const defaultSDID = "fields@32473"
+
+const rfc5424Timestamp = "2006-01-02T15:04:05.000000Z07:00"
+
+// Severity maps level to syslog severity based on level severity,
+// user defined level between warn and info is mapped to notice.
+func Severity(lv slog.Level) int {
+	sev := lv.Severity()
+	switch {
+	case sev >= slog.PanicSeverity:
+		return SevAlert
+	case sev >= slog.FatalSeverity:
+		return SevCritical
+	case sev >= slog.ErrorSeverity:
+		return SevError
+	case sev >= slog.WarnSeverity:
+		return SevWarning
+	case sev > slog.InfoSeverity:
+		return SevNotice
+	case sev >= slog.InfoSeverity:
+		return SevInfo
+	default:
+		return SevDebug
+	}
+}
+
+// ParseFacility from name (e.g. `local0`) or number
+func ParseFacility(s string) (Facility, bool) {
+	if f, ok := facilityStrMap[strings.ToLower(s)]; ok {
+		return f, true
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= int(Local7) {
+		return Facility(n), true
+	}
+	return 0, false
+}
+
+func priority(f Facility, lv slog.Level) int {
+	return int(f)*8 + Severity(lv)
+}
+
+// RFC5424Encoder formats entry according to RFC 5424.
+// Fields are written as parameters of structured data element SDID,
+// logger name is used as MSGID.
+type RFC5424Encoder struct {
+	Facility Facility
+	Hostname string
+	AppName  string
+	ProcID   string
+	SDID     string
+}
+
+// Encode entry
+func (enc *RFC5424Encoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(priority(enc.Facility, e.Level)))
+	buf.WriteString(">1 ")
+	buf.WriteString(e.Time.Format(rfc5424Timestamp))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(enc.Hostname, 255))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(enc.AppName, 48))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(enc.ProcID, 128))
+	buf.WriteByte(' ')
+	buf.WriteString(headerField(e.Logger, 32))
+	buf.WriteByte(' ')
+
+	// structured data
+	if len(e.Fields) == 0 && e.Caller == nil {
+		buf.WriteByte('-')
+	} else {
+		sdID := enc.SDID
+		if sdID == "" {
+			sdID = defaultSDID
+		}
+		buf.WriteByte('[')
+		buf.WriteString(sdName(sdID))
+		if e.Caller != nil {
+			writeSDParam(buf, "caller", e.Caller.String())
+		}
+		for _, f := range slog.FlattenFields(e.Fields) {
+			str, _ := slog.AsString(f.Value)
+			writeSDParam(buf, f.Key, str)
+		}
+		buf.WriteByte(']')
+	}
+
+	if e.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(e.Message)
+	}
+
+	return nil
+}
+
+func writeSDParam(buf *bytes.Buffer, name, val string) {
+	buf.WriteByte(' ')
+	buf.WriteString(sdName(name))
+	buf.WriteString(`="`)
+	for _, r := range val {
+		switch r {
+		case '"', '\\', ']':
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('"')
+}
+
+// header field is printable US-ASCII, nil value is written as `-`
+func headerField(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > max {
+		b = b[:max]
+	}
+	return string(b)
+}
+
+// SD-NAME is printable US-ASCII except '=', ' ', ']', '"' with max 32 chars
+func sdName(s string) string {
+	if s == "" {
+		return slog.UnknownFieldName
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	return string(b)
+}
+
+// RFC3164Encoder formats entry according to RFC 3164 (BSD syslog).
+// Fields are appended to the message as key=value.
+type RFC3164Encoder struct {
+	Facility Facility
+	Hostname string
+	Tag      string
+	PID      string
+}
+
+// Encode entry
+func (enc *RFC3164Encoder) Encode(buf *bytes.Buffer, e *slog.Entry) error {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(priority(enc.Facility, e.Level)))
+	buf.WriteByte('>')
+	buf.WriteString(e.Time.Format(time.Stamp))
+	buf.WriteByte(' ')
+	if enc.Hostname != "" {
+		buf.WriteString(headerField(enc.Hostname, 255))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(headerField(enc.Tag, 32))
+	if enc.PID != "" {
+		buf.WriteByte('[')
+		buf.WriteString(enc.PID)
+		buf.WriteByte(']')
+	}
+	buf.WriteString(": ")
+	buf.WriteString(e.Message)
+	if e.Caller != nil {
+		buf.WriteString(" caller=")
+		buf.WriteString(slog.AsStringQ(e.Caller.String()))
+	}
+	for _, f := range slog.FlattenFields(e.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(slog.AsStringQ(f.Value))
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package syslog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ipsusila/slog"
+)
+
+// Name of syslog logger
+const Name = "syslog"
+
+type syslogConstructor struct{}
+
+const (
+	fieldNetwork      = "network"
+	fieldAddress      = "address"
+	fieldFormat       = "format"
+	fieldFraming      = "framing"
+	fieldFacility     = "facility"
+	fieldHostname     = "hostname"
+	fieldAppName      = "appName"
+	fieldProcID       = "procID"
+	fieldSDID         = "sdID"
+	fieldName         = "name"
+	fieldReportCaller = "reportCaller"
+)
+
+// options supported by syslog logger
+var syslogSchema = slog.Schema{
+	{Name: fieldNetwork, Type: slog.StringOption, Default: "", Description: "network, empty for local syslog socket",
+		Values: []string{"", "unixgram", "unix", "udp", "tcp"}},
+	{Name: fieldAddress, Type: slog.StringOption, Default: "", Description: "socket path or host:port"},
+	{Name: fieldFormat, Type: slog.StringOption, Default: "rfc5424", Description: "message format",
+		Values: []string{"rfc5424", "rfc3164"}},
+	{Name: fieldFraming, Type: slog.StringOption, Default: "default", Description: "framing for stream transport",
+		Values: []string{"default", "octet", "newline", "null", "none"}},
+	{Name: fieldFacility, Type: slog.AnyOption, Default: "user", Description: "facility name or number"},
+	{Name: fieldHostname, Type: slog.StringOption, Description: "hostname, default to os.Hostname"},
+	{Name: fieldAppName, Type: slog.StringOption, Description: "application name (tag), default to program name"},
+	{Name: fieldProcID, Type: slog.StringOption, Description: "process ID, default to current pid"},
+	{Name: fieldSDID, Type: slog.StringOption, Default: defaultSDID, Description: "structured data ID for fields (rfc5424)"},
+	{Name: fieldName, Type: slog.StringOption, Default: "", Description: "logger name, used as MSGID (rfc5424)"},
+	{Name: fieldReportCaller, Type: slog.BoolOption, Default: false, Description: "add caller to each entry"},
+	slog.OptionLevelMode,
+	slog.OptionDuplicateKeys,
+	slog.OptionSortFields,
+	slog.OptionRedact,
+	slog.OptionRedactKeys,
+	slog.OptionRedactPatterns,
+	slog.OptionRedactMessages,
+}
+
+func init() {
+	slog.Register(Name, &syslogConstructor{})
+}
+
+// New creates syslog logger. If w is not nil, messages are written to w,
+// otherwise connection is made according to `network` and `address` options.
+func New(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	if err := syslogSchema.Validate(op); err != nil {
+		return nil, err
+	}
+	return newLogger(w, l, op)
+}
+
+func newLogger(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	policy, err := op.GetFieldPolicy()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := op.GetRedactor()
+	if err != nil {
+		return nil, err
+	}
+	enc, err := NewEncoder(op)
+	if err != nil {
+		return nil, err
+	}
+
+	if w == nil {
+		framing := framingStrMap[strings.ToLower(op.GetString(fieldFraming, "default"))]
+		conn, err := Dial(strings.ToLower(op.GetString(fieldNetwork, "")), op.GetString(fieldAddress, ""), framing)
+		if err != nil {
+			return nil, err
+		}
+		w = conn
+	}
+
+	lg := slog.NewHandlerLogger(slog.NewWriterHandler(w, enc), 0)
+	lg.SetName(op.GetString(fieldName, ""))
+	lg.SetReportCaller(op.GetBool(fieldReportCaller, false))
+	lg.SetFieldPolicy(policy)
+	lg.SetRedactor(redactor)
+	lg.SetLevelMode(op.GetLevelMode(slog.OptionLevelMode.Name, slog.ThresholdMode))
+	lg.SetLevel(l)
+
+	return lg, nil
+}
+
+// NewEncoder creates RFC 5424 or RFC 3164 encoder from options
+func NewEncoder(op slog.Options) (slog.Encoder, error) {
+	facility := User
+	if val, ok := op[fieldFacility]; ok {
+		f, ok := ParseFacility(fmt.Sprint(val))
+		if !ok {
+			return nil, fmt.Errorf("syslog: unknown facility %v", val)
+		}
+		facility = f
+	}
+
+	hostname := op.GetString(fieldHostname, "")
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	appName := op.GetString(fieldAppName, "")
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+	procID := op.GetString(fieldProcID, "")
+	if procID == "" {
+		procID = strconv.Itoa(os.Getpid())
+	}
+
+	switch strings.ToLower(op.GetString(fieldFormat, "rfc5424")) {
+	case "rfc3164":
+		return &RFC3164Encoder{
+			Facility: facility,
+			Hostname: hostname,
+			Tag:      appName,
+			PID:      procID,
+		}, nil
+	default:
+		return &RFC5424Encoder{
+			Facility: facility,
+			Hostname: hostname,
+			AppName:  appName,
+			ProcID:   procID,
+			SDID:     op.GetString(fieldSDID, defaultSDID),
+		}, nil
+	}
+}
+
+func (c *syslogConstructor) New(w io.Writer, l slog.Level) (slog.Logger, error) {
+	return newLogger(w, l, nil)
+}
+func (c *syslogConstructor) NewWithOptions(w io.Writer, l slog.Level, op slog.Options) (slog.Logger, error) {
+	return newLogger(w, l, op)
+}
+
+// Schema return options supported by syslog logger
+func (c *syslogConstructor) Schema() slog.Schema {
+	return syslogSchema
+}
@@ -0,0 +1,209 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipsusila/slog"
+)
+
+var testTime = time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+func testEntry(lv slog.Level, msg string, keyVals ...interface{}) *slog.Entry {
+	e := slog.NewEntry(lv, msg, keyVals)
+	e.Time = testTime
+	return e
+}
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  slog.Encoder
+		e    *slog.Entry
+		want string
+	}{
+		{
+			"rfc5424 without fields",
+			&RFC5424Encoder{Facility: User, Hostname: "host", AppName: "app", ProcID: "42"},
+			testEntry(slog.InfoLevel, "started"),
+			"<14>1 2024-05-06T07:08:09.123456Z host app 42 - - started",
+		},
+		{
+			"rfc5424 with fields",
+			&RFC5424Encoder{Facility: Local0, Hostname: "host", AppName: "app", ProcID: "42", SDID: "x@1"},
+			testEntry(slog.ErrorLevel, "failed", "path", `a"b]`, "n", 1),
+			`<131>1 2024-05-06T07:08:09.123456Z host app 42 - [x@1 path="a\"b\]" n="1"] failed`,
+		},
+		{
+			"rfc5424 nil header fields",
+			&RFC5424Encoder{Facility: User},
+			testEntry(slog.WarnLevel, ""),
+			"<12>1 2024-05-06T07:08:09.123456Z - - - - -",
+		},
+		{
+			"rfc3164",
+			&RFC3164Encoder{Facility: Daemon, Hostname: "host", Tag: "app", PID: "42"},
+			testEntry(slog.DebugLevel, "tick", "n", 1),
+			"<31>May  6 07:08:09 host app[42]: tick n=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.enc.Encode(&buf, tt.e); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		lv   slog.Level
+		want int
+	}{
+		{slog.PanicLevel, SevAlert},
+		{slog.FatalLevel, SevCritical},
+		{slog.ErrorLevel, SevError},
+		{slog.WarnLevel, SevWarning},
+		{slog.InfoLevel, SevInfo},
+		{slog.DebugLevel, SevDebug},
+		{slog.TraceLevel, SevDebug},
+	}
+	for _, tt := range tests {
+		if got := Severity(tt.lv); got != tt.want {
+			t.Errorf("Severity(%v) = %d, want %d", tt.lv, got, tt.want)
+		}
+	}
+}
+
+// listen on local tcp and return channel receiving accepted connection
+func listenTCP(t *testing.T) (string, <-chan net.Conn) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	ch := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			ch <- conn
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func accept(t *testing.T, ch <-chan net.Conn) net.Conn {
+	t.Helper()
+	select {
+	case conn := <-ch:
+		t.Cleanup(func() { conn.Close() })
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection")
+	}
+	return nil
+}
+
+// readOctetCounted reads `MSG-LEN SP SYSLOG-MSG` frame
+func readOctetCounted(r *bufio.Reader) (string, error) {
+	head, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(head, " "))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return "", err
+	}
+	return string(msg), nil
+}
+
+func TestOctetCountingFraming(t *testing.T) {
+	addr, ch := listenTCP(t)
+	lg, err := New(nil, slog.InfoLevel, slog.Options{
+		"network":  "tcp",
+		"address":  addr,
+		"hostname": "host",
+		"appName":  "app",
+		"procID":   "42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.(*slog.HandlerLogger).Close()
+	conn := accept(t, ch)
+
+	msgs := []struct {
+		msg     string
+		keyVals []interface{}
+		want    string
+	}{
+		{"first", nil, " host app 42 - - first"},
+		{"multi byte ünïcödé", []interface{}{"k", "välue"}, ` host app 42 - [fields@32473 k="välue"] multi byte ünïcödé`},
+		{"line\nbreak", nil, " - - line\nbreak"},
+	}
+	for _, m := range msgs {
+		lg.Infow(m.msg, m.keyVals...)
+	}
+
+	r := bufio.NewReader(conn)
+	for _, m := range msgs {
+		got, err := readOctetCounted(r)
+		if err != nil {
+			t.Fatalf("read frame of %q: %v", m.msg, err)
+		}
+		if !strings.HasPrefix(got, "<14>1 ") || !strings.HasSuffix(got, m.want) {
+			t.Errorf("frame %q, want suffix %q", got, m.want)
+		}
+	}
+}
+
+func TestStreamFraming(t *testing.T) {
+	tests := []struct {
+		framing string
+		want    string
+	}{
+		{"newline", "<14>msg\n"},
+		{"null", "<14>msg\x00"},
+		{"octet", "7 <14>msg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.framing, func(t *testing.T) {
+			addr, ch := listenTCP(t)
+			framing := framingStrMap[tt.framing]
+			c, err := Dial("tcp", addr, framing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			conn := accept(t, ch)
+
+			if _, err := c.Write([]byte("<14>msg")); err != nil {
+				t.Fatal(err)
+			}
+			got := make([]byte, len(tt.want))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
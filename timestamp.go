@@ -0,0 +1,112 @@
+package slog
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Special timestamp formats accepted by TimestampFormat of text, pretty, json and logfmt encoders,
+// other values are time layouts
+const (
+	// TimestampNone disables timestamp
+	TimestampNone = "none"
+	// TimestampEpoch writes seconds since Unix epoch
+	TimestampEpoch = "epoch"
+	// TimestampEpochMillis writes milliseconds since Unix epoch
+	TimestampEpochMillis = "epoch_ms"
+	// TimestampEpochNanos writes nanoseconds since Unix epoch
+	TimestampEpochNanos = "epoch_ns"
+	// TimestampRFC3339Nano writes time.RFC3339Nano
+	TimestampRFC3339Nano = "rfc3339nano"
+	// TimestampElapsed writes time elapsed since process start (or SetClock of package or logger)
+	TimestampElapsed = "elapsed"
+)
+
+// Option names of timestamp
+const (
+	fieldTimestampUTC     = "timestampUTC"
+	fieldDisableTimestamp = "disableTimestamp"
+)
+
+// Clock return current time used as entry timestamp
+type Clock func() time.Time
+
+type clockHolder struct {
+	now   Clock
+	start time.Time
+}
+
+var clock atomic.Value
+
+func init() {
+	clock.Store(clockHolder{now: time.Now, start: time.Now()})
+}
+
+// SetClock replaces clock used for entry timestamp (e.g. fixed time in tests),
+// nil restores time.Now. Start of elapsed timestamp is reset to current time of the clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = time.Now
+	}
+	clock.Store(clockHolder{now: c, start: c()})
+}
+
+// Now return current time of the clock
+func Now() time.Time {
+	return clock.Load().(clockHolder).now()
+}
+
+// Elapsed return time elapsed from process start (or SetClock) until t
+func Elapsed(t time.Time) time.Duration {
+	return t.Sub(clock.Load().(clockHolder).start)
+}
+
+// Elapsed return time elapsed from Start (or start of the package clock) until entry time
+func (e *Entry) Elapsed() time.Duration {
+	if e.Start.IsZero() {
+		return Elapsed(e.Time)
+	}
+	return e.Time.Sub(e.Start)
+}
+
+// FormatTimestamp formats entry time as FormatTimestamp, elapsed timestamp is
+// measured by Entry.Elapsed
+func (e *Entry) FormatTimestamp(format string, utc bool) (ts string, numeric, ok bool) {
+	if strings.EqualFold(format, TimestampElapsed) {
+		return e.Elapsed().Round(time.Millisecond).String(), false, true
+	}
+	return FormatTimestamp(e.Time, format, utc)
+}
+
+// FormatTimestamp formats t using layout or one of special formats (TimestampEpoch, ...),
+// in UTC when utc is set. numeric is set for epoch formats and ok is false for TimestampNone.
+func FormatTimestamp(t time.Time, format string, utc bool) (ts string, numeric, ok bool) {
+	if utc {
+		t = t.UTC()
+	}
+	switch strings.ToLower(format) {
+	case TimestampNone:
+		return "", false, false
+	case TimestampEpoch:
+		return strconv.FormatInt(t.Unix(), 10), true, true
+	case TimestampEpochMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), true, true
+	case TimestampEpochNanos:
+		return strconv.FormatInt(t.UnixNano(), 10), true, true
+	case TimestampRFC3339Nano:
+		return t.Format(time.RFC3339Nano), false, true
+	case TimestampElapsed:
+		return Elapsed(t).Round(time.Millisecond).String(), false, true
+	}
+	return t.Format(format), false, true
+}
+
+// timestamp format from timestampFormat and disableTimestamp options
+func timestampFormat(op Options, def string) string {
+	if op.GetBool(fieldDisableTimestamp, false) {
+		return TimestampNone
+	}
+	return op.GetString(fieldTimestampFormat, def)
+}
@@ -0,0 +1,149 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("WIB", 7*3600))
+	SetClock(func() time.Time { return fixed })
+	defer SetClock(nil)
+
+	tests := []struct {
+		name        string
+		t           time.Time
+		format      string
+		utc         bool
+		want        string
+		wantNumeric bool
+		wantOK      bool
+	}{
+		{"none", fixed, TimestampNone, false, "", false, false},
+		{"epoch", fixed, TimestampEpoch, false, "1714954089", true, true},
+		{"epoch ms", fixed, TimestampEpochMillis, false, "1714954089123", true, true},
+		{"epoch ns", fixed, TimestampEpochNanos, false, "1714954089123456789", true, true},
+		{"case insensitive", fixed, "EPOCH_MS", false, "1714954089123", true, true},
+		{"rfc3339nano", fixed, TimestampRFC3339Nano, false, "2024-05-06T07:08:09.123456789+07:00", false, true},
+		{"rfc3339nano utc", fixed, TimestampRFC3339Nano, true, "2024-05-06T00:08:09.123456789Z", false, true},
+		{"layout", fixed, "2006-01-02 15:04:05 MST", false, "2024-05-06 07:08:09 WIB", false, true},
+		{"layout utc", fixed, "2006-01-02 15:04:05 MST", true, "2024-05-06 00:08:09 UTC", false, true},
+		{"elapsed", fixed.Add(1500 * time.Millisecond), TimestampElapsed, false, "1.5s", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, numeric, ok := FormatTimestamp(tt.t, tt.format, tt.utc)
+			if got != tt.want || numeric != tt.wantNumeric || ok != tt.wantOK {
+				t.Errorf("FormatTimestamp() = %q, %v, %v, want %q, %v, %v",
+					got, numeric, ok, tt.want, tt.wantNumeric, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoggerClock(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{"text", Options{"timestampFormat": "2006-01-02T15:04:05", "color": "never"}, "INFOO [2024-05-06T07:08:09] hello\n"},
+		{"logfmt epoch", Options{"formatter": "logfmt", "timestampFormat": "epoch"}, "time=1714979289 level=info msg=hello\n"},
+		{"json rfc3339nano", Options{"formatter": "json", "timestampFormat": "rfc3339nano", "timestampUTC": true},
+			`{"time":"2024-05-06T07:08:09Z","level":"info","msg":"hello"}` + "\n"},
+		{"json epoch is number", Options{"formatter": "json", "timestampFormat": "epoch_ms"},
+			`{"time":1714979289000,"level":"info","msg":"hello"}` + "\n"},
+		{"disabled", Options{"formatter": "logfmt", "disableTimestamp": true}, "level=info msg=hello\n"},
+		{"ecs", Options{"formatter": "ecs"}, `"@timestamp":"2024-05-06T07:08:09.000Z"`},
+		{"gcp", Options{"formatter": "gcp"}, `"time":"2024-05-06T07:08:09Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			lg, err := NewStdLogger(&buf, InfoLevel, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lg.(*HandlerLogger).SetClock(func() time.Time { return fixed })
+			lg.Info("hello")
+			// ecs and gcp entries are checked by timestamp only
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerClockElapsed(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{"text", Options{"timestampFormat": "elapsed", "color": "never"}, "INFOO [1.5s] hello\n"},
+		{"json", Options{"formatter": "json", "timestampFormat": "elapsed"}, `{"time":"1.5s","level":"info","msg":"hello"}` + "\n"},
+		{"pretty", Options{"formatter": "pretty", "color": "never", "nameWidth": -1}, "    1.500s INFOO hello\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			lg, err := NewStdLogger(&buf, InfoLevel, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// elapsed is measured from the time the clock is set, shared by children
+			now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+			hl := lg.(*HandlerLogger)
+			hl.SetClock(func() time.Time { return now })
+			now = now.Add(1500 * time.Millisecond)
+			hl.WithGroup("req").Info("hello")
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryElapsed(t *testing.T) {
+	start := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	e := &Entry{Time: start.Add(2 * time.Second), Start: start}
+	if got := e.Elapsed(); got != 2*time.Second {
+		t.Errorf("Elapsed() = %v with start", got)
+	}
+	if ts, _, _ := e.FormatTimestamp("Elapsed", false); ts != "2s" {
+		t.Errorf("FormatTimestamp() = %q", ts)
+	}
+
+	// without start, elapsed is measured from the package clock
+	SetClock(func() time.Time { return start })
+	defer SetClock(nil)
+	e.Start = time.Time{}
+	if got := e.Elapsed(); got != 2*time.Second {
+		t.Errorf("Elapsed() = %v without start", got)
+	}
+}
+
+func TestEncoderTimestampOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{
+		{"text with format", Options{"timestampFormat": "epoch"}, false},
+		{"ecs", Options{"formatter": "ecs"}, false},
+		{"ecs with format", Options{"formatter": "ecs", "timestampFormat": "epoch"}, true},
+		{"gcp with utc", Options{"formatter": "GCP", "timestampUTC": true}, true},
+		{"gcp without timestamp", Options{"formatter": "gcp", "disableTimestamp": true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStdLogger(&bytes.Buffer{}, InfoLevel, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewStdLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
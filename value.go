@@ -0,0 +1,219 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LogValuer is implemented by types which control how they are logged,
+// LogValue return the value written instead (scalar, ObjectMarshaler or another LogValuer).
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// Redacted is implemented by types which must never be logged as is,
+// value returned by Redacted is written instead. It takes precedence
+// over LogValuer and ObjectMarshaler, with or without a Redactor.
+type Redacted interface {
+	Redacted() interface{}
+}
+
+// ObjectEncoder receives named sub-fields of an object
+type ObjectEncoder interface {
+	AddField(key string, val interface{})
+}
+
+// ObjectMarshaler is implemented by types which are logged as nested object
+type ObjectMarshaler interface {
+	MarshalLogObject(enc ObjectEncoder) error
+}
+
+// MaxValueDepth limits nesting of LogValuer and ObjectMarshaler values
+const MaxValueDepth = 10
+
+// Placeholders written instead of value which can not be resolved
+const (
+	CycleValue    = "<cycle>"
+	MaxDepthValue = "<max depth>"
+)
+
+// key of field added when MarshalLogObject returns error
+const marshalErrorKey = "marshalError"
+
+// Object is ordered sub-fields of resolved ObjectMarshaler.
+// JSON encoder writes it as nested object, text and logfmt encoders
+// as dotted keys (e.g. user.id=1).
+type Object []Field
+
+// AddField appends sub-field
+func (o *Object) AddField(key string, val interface{}) {
+	*o = append(*o, Field{Key: key, Value: val})
+}
+
+// String formats object as {key=value ...}
+func (o Object) String() string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(f.Key)
+		sb.WriteByte('=')
+		sb.WriteString(AsStringQ(f.Value))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// MarshalJSON writes object with keys in order
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		writeJSONKey(&buf, f.Key, i == 0)
+		writeJSONValue(&buf, f.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Map converts object (including nested objects) into map
+func (o Object) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(o))
+	for _, f := range o {
+		if child, ok := f.Value.(Object); ok {
+			m[f.Key] = child.Map()
+		} else {
+			m[f.Key] = f.Value
+		}
+	}
+	return m
+}
+
+// ResolveValue resolves Redacted, LogValuer and ObjectMarshaler (into Object) recursively.
+// Cycles and nesting deeper than MaxValueDepth are replaced with placeholder,
+// panic in Redacted, LogValue or MarshalLogObject is written as value.
+func ResolveValue(val interface{}) interface{} {
+	return resolveValue(val, 0, nil)
+}
+
+func resolveValue(val interface{}, depth int, seen []uintptr) interface{} {
+	for {
+		switch v := val.(type) {
+		case Redacted, LogValuer, ObjectMarshaler:
+			if depth >= MaxValueDepth {
+				return MaxDepthValue
+			}
+			if p, ok := pointerOf(v); ok {
+				for _, s := range seen {
+					if s == p {
+						return CycleValue
+					}
+				}
+				seen = append(seen, p)
+			}
+			depth++
+			if rv, ok := v.(Redacted); ok {
+				val = callValue(rv.Redacted)
+				continue
+			}
+			if lv, ok := v.(LogValuer); ok {
+				val = callValue(lv.LogValue)
+				continue
+			}
+			return marshalObject(v.(ObjectMarshaler), depth, seen)
+		case Field:
+			// group used as value
+			val = Object{v}
+		case Object:
+			// e.g. group, values are not resolved yet
+			if depth >= MaxValueDepth {
+				return MaxDepthValue
+			}
+			o := make(Object, len(v))
+			for i, f := range v {
+				o[i] = Field{Key: f.Key, Value: resolveValue(f.Value, depth+1, seen)}
+			}
+			return o
+		default:
+			return val
+		}
+	}
+}
+
+func pointerOf(val interface{}) (uintptr, bool) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		return rv.Pointer(), true
+	}
+	return 0, false
+}
+
+func callValue(fn func() interface{}) (val interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			val = fmt.Sprintf("<panic: %v>", r)
+		}
+	}()
+	return fn()
+}
+
+func marshalObject(om ObjectMarshaler, depth int, seen []uintptr) Object {
+	var o Object
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return om.MarshalLogObject(&o)
+	}()
+	for i := range o {
+		o[i].Value = resolveValue(o[i].Value, depth, seen)
+	}
+	if err != nil {
+		o = append(o, Field{Key: marshalErrorKey, Value: err.Error()})
+	}
+	return o
+}
+
+// ResolveFields resolves value of each field in place
+func ResolveFields(fields []Field) []Field {
+	for i := range fields {
+		fields[i].Value = ResolveValue(fields[i].Value)
+	}
+	return fields
+}
+
+// FlattenFields return fields with Object value expanded into dotted keys,
+// the same slice is returned if there is no Object value
+func FlattenFields(fields []Field) []Field {
+	nested := false
+	for _, f := range fields {
+		if _, ok := f.Value.(Object); ok {
+			nested = true
+			break
+		}
+	}
+	if !nested {
+		return fields
+	}
+
+	flat := make([]Field, 0, len(fields)+4)
+	return appendFlat(flat, "", fields)
+}
+
+func appendFlat(dst []Field, prefix string, fields []Field) []Field {
+	for _, f := range fields {
+		key := prefix + f.Key
+		if o, ok := f.Value.(Object); ok && len(o) > 0 {
+			dst = appendFlat(dst, key+".", o)
+			continue
+		}
+		dst = append(dst, Field{Key: key, Value: f.Value})
+	}
+	return dst
+}
@@ -0,0 +1,144 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// masked LogValuer
+type password string
+
+func (p password) LogValue() interface{} { return "***" }
+
+// LogValuer returning ObjectMarshaler
+type userRef struct{ u *user }
+
+func (r userRef) LogValue() interface{} { return r.u }
+
+type user struct {
+	id     int
+	name   string
+	friend *user
+}
+
+func (u *user) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddField("id", u.id)
+	enc.AddField("name", u.name)
+	if u.friend != nil {
+		enc.AddField("friend", u.friend)
+	}
+	return nil
+}
+
+// LogValuer wrapping itself n times
+type nested int
+
+func (n nested) LogValue() interface{} {
+	if n == 0 {
+		return "bottom"
+	}
+	return n - 1
+}
+
+type panicValuer struct{}
+
+func (panicValuer) LogValue() interface{} { panic("boom") }
+
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddField("id", 1)
+	panic("boom")
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddField("id", 1)
+	return errors.New("partial")
+}
+
+func TestResolveValue(t *testing.T) {
+	cyclic := &user{id: 1, name: "bob"}
+	cyclic.friend = &user{id: 2, name: "alice", friend: cyclic}
+	deep := &user{id: 0}
+	for i, u := 1, deep; i <= MaxValueDepth; i++ {
+		u.friend = &user{id: i}
+		u = u.friend
+	}
+
+	tests := []struct {
+		name string
+		val  interface{}
+		want interface{}
+	}{
+		{"scalar", 42, 42},
+		{"log valuer", password("hunter2"), "***"},
+		{"object marshaler", &user{id: 1, name: "bob"}, Object{{"id", 1}, {"name", "bob"}}},
+		{"log valuer to object", userRef{&user{id: 1, name: "bob"}}, Object{{"id", 1}, {"name", "bob"}}},
+		{"nested object", &user{id: 1, name: "bob", friend: &user{id: 2, name: "alice"}},
+			Object{{"id", 1}, {"name", "bob"}, {"friend", Object{{"id", 2}, {"name", "alice"}}}}},
+		{"group values", Object{{"pw", password("x")}}, Object{{"pw", "***"}}},
+		{"cycle", cyclic, Object{{"id", 1}, {"name", "bob"},
+			{"friend", Object{{"id", 2}, {"name", "alice"}, {"friend", CycleValue}}}}},
+		{"log valuer within depth", nested(MaxValueDepth - 1), "bottom"},
+		{"log valuer depth cap", nested(MaxValueDepth), MaxDepthValue},
+		{"log value panic", panicValuer{}, "<panic: boom>"},
+		{"marshal panic", panicMarshaler{}, Object{{"id", 1}, {"marshalError", "panic: boom"}}},
+		{"marshal error", failingMarshaler{}, Object{{"id", 1}, {"marshalError", "partial"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveValue(tt.val); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// object nesting is capped too
+	o, _ := ResolveValue(deep).(Object)
+	for depth := 1; depth < MaxValueDepth; depth++ {
+		o, _ = o[len(o)-1].Value.(Object)
+	}
+	if o[0].Value != MaxValueDepth-1 || o[len(o)-1].Value != MaxDepthValue {
+		t.Errorf("object at max depth %#v, want %q", o, MaxDepthValue)
+	}
+}
+
+func TestObjectRendering(t *testing.T) {
+	u := &user{id: 1, name: "bob", friend: &user{id: 2, name: "alice"}}
+
+	m := FieldsToMap([]interface{}{"user", u, "n", 1})
+	want := map[string]interface{}{
+		"user": map[string]interface{}{"id": 1, "name": "bob",
+			"friend": map[string]interface{}{"id": 2, "name": "alice"}},
+		"n": 1,
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("FieldsToMap() = %v, want %v", m, want)
+	}
+
+	tests := []struct {
+		name string
+		enc  Encoder
+		want string
+	}{
+		{"json", &JSONEncoder{TimestampFormat: TimestampNone},
+			`{"level":"info","msg":"m","user":{"id":1,"name":"bob","friend":{"id":2,"name":"alice"}},"n":1}` + "\n"},
+		{"logfmt", &LogfmtEncoder{TimestampFormat: TimestampNone},
+			`level=info msg=m user.id=1 user.name=bob user.friend.id=2 user.friend.name=alice n=1` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.enc.Encode(&buf, NewEntry(InfoLevel, "m", []interface{}{"user", u, "n", 1})); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}